@@ -1,3 +1,3 @@
 module cos316.princeton.edu/assignment2
 
-go 1.17
+go 1.21
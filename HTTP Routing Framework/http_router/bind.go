@@ -0,0 +1,143 @@
+/*****************************************************************************
+ * bind.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// BindError is returned when a request can't be bound into a value, because its body is malformed or one of its
+// values can't be converted to the type of the field it is bound into. It should be answered with a 400.
+type BindError struct {
+	Field string // name the failing value was given under, or empty for a malformed body
+	Err   error
+}
+
+// Error describes the value that failed to bind
+func (err *BindError) Error() string {
+	if err.Field == "" {
+		return "http_router: invalid request body: " + err.Err.Error()
+	}
+	return fmt.Sprintf("http_router: invalid value for %s: %v", err.Field, err.Err)
+}
+
+// Unwrap returns the error the value failed with
+func (err *BindError) Unwrap() error {
+	return err.Err
+}
+
+// errNotStructPointer is the error binding into anything but a pointer to a struct panics with
+var errNotStructPointer = errors.New("http_router: can only bind into a pointer to a struct")
+
+// textUnmarshalerType is the type of encoding.TextUnmarshaler, which fields can implement to be bound from text
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// bindValues sets the fields of the struct target points to that have the given tag, e.g. `form:"name"`, from the
+// values lookup finds under the tag's name. Fields of embedded structs are bound too, and fields whose tag is "-" or
+// whose name has no values are left as they are. Fields can be strings, booleans, numbers, types implementing
+// encoding.TextUnmarshaler, pointers to any of these, or slices of any of these, which take every value of their
+// name rather than the first. Targets other than pointers to structs panic.
+func bindValues(target any, tag string, lookup func(name string) []string) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		panic(errNotStructPointer)
+	}
+	return bindStruct(value.Elem(), tag, lookup)
+}
+
+// bindStruct is a helper function for bindValues that binds the fields of a struct value
+func bindStruct(value reflect.Value, tag string, lookup func(name string) []string) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindStruct(value.Field(i), tag, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, ok := field.Tag.Lookup(tag)
+		if !ok || name == "-" || !field.IsExported() {
+			continue
+		}
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+		if err := setField(value.Field(i), values); err != nil {
+			return &BindError{Field: name, Err: err}
+		}
+	}
+	return nil
+}
+
+// setField sets field from values, all of them for a slice field and the first one otherwise
+func setField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && !field.Type().Implements(textUnmarshalerType) &&
+		field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, text := range values {
+			if err := setText(slice.Index(i), text); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setText(field, values[0])
+}
+
+// setText sets a single field from text
+func setText(field reflect.Value, text string) error {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		return setText(field.Elem(), text)
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
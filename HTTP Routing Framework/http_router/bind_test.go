@@ -0,0 +1,91 @@
+/******************************************************************************
+ *  bind_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for binding request values into the tagged fields of structs.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// lookupMap returns a lookup for bindValues that finds values in a map
+func lookupMap(values map[string][]string) func(name string) []string {
+	return func(name string) []string {
+		return values[name]
+	}
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestBindValues checks that every supported field type is bound, including embedded structs, pointers, slices and
+// text unmarshalers, while untagged, skipped and missing fields are left alone.
+func TestBindValues(t *testing.T) {
+	type Paging struct {
+		Page  uint    `form:"page"`
+		Ratio float64 `form:"ratio"`
+	}
+	type target struct {
+		Paging
+		Name     string   `form:"name"`
+		Count    int8     `form:"count"`
+		Active   bool     `form:"active"`
+		IDs      []int    `form:"id"`
+		Limit    *int     `form:"limit"`
+		Address  net.IP   `form:"ip"`
+		Skipped  string   `form:"-"`
+		Untagged string   // not bound
+		Missing  []string `form:"missing"`
+	}
+
+	got := target{Skipped: "kept", Untagged: "kept"}
+	err := bindValues(&got, "form", lookupMap(map[string][]string{
+		"page": {"2"}, "ratio": {"0.5"}, "name": {"bob", "alice"}, "count": {"-3"}, "active": {"true"},
+		"id": {"1", "2"}, "limit": {"10"}, "ip": {"10.0.0.1"}, "-": {"lost"}, "Untagged": {"lost"},
+	}))
+	if err != nil {
+		t.Fatalf("Test failed: Expected no error and received %v.", err)
+	}
+
+	limit := 10
+	expected := target{
+		Paging: Paging{Page: 2, Ratio: 0.5}, Name: "bob", Count: -3, Active: true, IDs: []int{1, 2}, Limit: &limit,
+		Address: net.ParseIP("10.0.0.1"), Skipped: "kept", Untagged: "kept",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Test failed: Expected %+v and received %+v.", expected, got)
+	}
+}
+
+// TestBindValuesErrors checks that a value that doesn't fit its field names the field and wraps the conversion
+// error, and that binding into anything but a pointer to a struct panics.
+func TestBindValuesErrors(t *testing.T) {
+	var got struct {
+		Count int8 `query:"count"`
+	}
+	err := bindValues(&got, "query", lookupMap(map[string][]string{"count": {"300"}}))
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) || bindErr.Field != "count" || !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("Test failed: Expected a range error for count and received %v.", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != errNotStructPointer {
+			t.Errorf("Test failed: Expected %v and received %v.", errNotStructPointer, recovered)
+		}
+	}()
+	bindValues(got, "query", lookupMap(nil))
+}
@@ -0,0 +1,37 @@
+/*****************************************************************************
+ * case.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+// CasePolicy decides how the router matches the static directories of patterns against request directories that only
+// differ in case, such as `/Users/Alice/Recent` for the route `/users/:user/recent`. Captured values always keep the
+// case they were requested with.
+type CasePolicy int
+
+const (
+	// MatchCase only matches static directories of the same case. It is the default policy.
+	MatchCase CasePolicy = iota
+	// IgnoreCase matches static directories of any case and serves the request directly. A directory of the same
+	// case is still tried first, then the added directories differing only in case, in the order they were added.
+	IgnoreCase
+	// RedirectCase matches static directories as IgnoreCase does, but redirects requests whose static directories
+	// differ in case to the canonical path of their route, as RedirectPath redirects them
+	RedirectCase
+)
+
+// staticCaseDiffers reports whether the static directories of route are cased differently in the given request
+// directories matched by route
+func (route *RoutesField) staticCaseDiffers(requestSegments []string) bool {
+	for i, segment := range route.segments {
+		if isCatchAll(segment) {
+			break
+		}
+		if !isCapture(segment) && segment != requestSegments[i] {
+			return true
+		}
+	}
+	return false
+}
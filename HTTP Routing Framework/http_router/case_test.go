@@ -0,0 +1,158 @@
+/******************************************************************************
+ *  case_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for matching static directories regardless of case.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// newCaseRouter creates a router with the given policy and routes mixing static directories and captures
+func newCaseRouter(policy CasePolicy) *HTTPRouter {
+	router := NewRouter()
+	router.CasePolicy = policy
+	router.AddRoute(httpGet, "/users/:user/recent", echoParams)
+	router.AddRoute(httpPost, "/users/:user/recent", echoMethodHandler)
+	router.AddRoute(httpGet, "/users/me", echoPathHandler)
+	return router
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestMatchCase checks that static directories are matched case-sensitively by default.
+func TestMatchCase(t *testing.T) {
+	router := newCaseRouter(MatchCase)
+	if recorder := servePath(router, httpGet, "/Users/Alice/Recent"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Test failed: Expected a 404 and received %d.", recorder.Code)
+	}
+}
+
+// TestIgnoreCase checks that static directories of any case are served directly, with captures keeping the case
+// they were requested with, and that a static directory still takes precedence over a capture.
+func TestIgnoreCase(t *testing.T) {
+	router := newCaseRouter(IgnoreCase)
+	tests := map[string]string{
+		"/Users/Alice/Recent": "user=Alice",
+		"/USERS/bob/recent":   "user=bob",
+		"/users/ME":           "/users/ME",
+		"/users/Me/RECENT":    "user=Me",
+	}
+	for path, expected := range tests {
+		if body := servePath(router, httpGet, path).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+	if recorder := servePath(router, "DELETE", "/USERS/me"); recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Test failed: Expected a 405 and received %d.", recorder.Code)
+	}
+}
+
+// TestIgnoreCaseSameName checks that directories differing only in case are each served by their own route, that
+// the first added serves any other case, and that the other takes over when it is removed.
+func TestIgnoreCaseSameName(t *testing.T) {
+	router := NewRouter()
+	router.CasePolicy = IgnoreCase
+	router.AddRoute(httpGet, "/Docs", echoMethodHandler)
+	router.AddRoute(httpGet, "/docs", echoPathHandler)
+
+	tests := map[string]string{"/Docs": "GET", "/docs": "/docs", "/DOCS": "GET"}
+	for path, expected := range tests {
+		if body := servePath(router, httpGet, path).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+
+	router.RemoveRoute(httpGet, "/Docs")
+	if body := servePath(router, httpGet, "/DOCS").Body.String(); body != "/DOCS" {
+		t.Errorf("Test failed: Expected /docs to serve /DOCS and received %s.", body)
+	}
+	router.RemoveRoute(httpGet, "/docs")
+	if recorder := servePath(router, httpGet, "/DOCS"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Test failed: Expected a 404 and received %d.", recorder.Code)
+	}
+}
+
+// TestIgnoreCaseBacktrack checks that every directory differing only in case is tried, so a request is served by
+// the route under the second added one when the route under the first doesn't match the rest of the path.
+func TestIgnoreCaseBacktrack(t *testing.T) {
+	router := NewRouter()
+	router.CasePolicy = IgnoreCase
+	router.AddRoute(httpGet, "/Users/a", echoPathHandler)
+	router.AddRoute(httpGet, "/users/b", echoMethodHandler)
+
+	tests := map[string]string{"/USERS/a": "/USERS/a", "/USERS/b": "GET", "/Users/B": "GET", "/users/A": "/users/A"}
+	for path, expected := range tests {
+		if body := servePath(router, httpGet, path).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+
+	router.RemoveRoute(httpGet, "/users/b")
+	if recorder := servePath(router, httpGet, "/USERS/b"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Test failed: Expected a 404 and received %d.", recorder.Code)
+	}
+}
+
+// TestRedirectCase checks that requests with differently cased static directories are redirected to the added case,
+// keeping the captured values, the query and, with a 308, the method.
+func TestRedirectCase(t *testing.T) {
+	router := newCaseRouter(RedirectCase)
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{httpGet, "/users/Alice/recent", http.StatusOK, ""},
+		{httpGet, "/Users/Alice/Recent", http.StatusMovedPermanently, "/users/Alice/recent?page=2"},
+		{httpGet, "/Users/Alice/Recent/", http.StatusMovedPermanently, "/users/Alice/recent?page=2"},
+		{httpPost, "/USERS/Alice/recent", http.StatusPermanentRedirect, "/users/Alice/recent?page=2"},
+	}
+	for _, test := range tests {
+		recorder := servePath(router, test.method, test.path)
+		if recorder.Code != test.status || recorder.Header().Get("Location") != test.location {
+			t.Errorf("Test failed: Expected %d %s for %s %s and received %d %s.", test.status, test.location,
+				test.method, test.path, recorder.Code, recorder.Header().Get("Location"))
+		}
+	}
+}
+
+// TestIgnoreCaseStrictPath checks that the strict path policy leaves the case of static directories to the case
+// policy.
+func TestIgnoreCaseStrictPath(t *testing.T) {
+	router := newCaseRouter(IgnoreCase)
+	router.PathPolicy = StrictPath
+	for path, expected := range map[string]int{"/Users/Alice/Recent": 200, "/Users/Alice/Recent/": 404} {
+		if recorder := servePath(router, httpGet, path); recorder.Code != expected {
+			t.Errorf("Test failed: Expected %d for %s and received %d.", expected, path, recorder.Code)
+		}
+	}
+}
+
+// TestRedirectCaseEmptyDirectory checks that a capture matching an empty first directory can't turn the redirect into
+// a link to another site, under the default and the strict path policy.
+func TestRedirectCaseEmptyDirectory(t *testing.T) {
+	for _, policy := range []PathPolicy{TrimSlashes, StrictPath} {
+		router := NewRouter()
+		router.CasePolicy = RedirectCase
+		router.PathPolicy = policy
+		router.AddRoute(httpGet, "/:a/:b/Home", echoParams)
+
+		recorder := servePath(router, httpGet, "//evil.com/home")
+		if location := recorder.Header().Get("Location"); location != "/evil.com/Home?page=2" {
+			t.Errorf("Test failed: Expected /evil.com/Home?page=2 under policy %d and received %d %s.", policy,
+				recorder.Code, location)
+		}
+	}
+}
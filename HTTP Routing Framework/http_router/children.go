@@ -0,0 +1,175 @@
+/*****************************************************************************
+ * children.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// children maps the static directory names under a trie node to their nodes, or for the folded index to the names
+// folding to them. It is a hash array mapped trie: each level uses 5 bits of the name's hash to pick one of 32 slots,
+// and only the occupied slots are stored. Like nodes, it is never modified once published. Changes copy the levels
+// along the changed name, so adding a route under a node with thousands of static children copies a few small arrays
+// rather than the whole map.
+//
+// A nil *children is the empty map.
+type children[V comparable] struct {
+	bitmap  uint32          // slots in use at this level
+	entries []childEntry[V] // one per slot in use, in slot order, or every colliding name once the hash is used up
+}
+
+// childEntry is either a single name and its value, or the next level for the names sharing a slot
+type childEntry[V comparable] struct {
+	name  string
+	value V
+	next  *children[V]
+}
+
+// hashBits is the number of hash bits used per level, and hashSize the number of bits in a hash
+const (
+	hashBits = 5
+	hashSize = 32
+)
+
+// hashName hashes a directory name with 32 bit FNV-1a
+func hashName(name string) uint32 {
+	hash := uint32(2166136261)
+	for i := 0; i < len(name); i++ {
+		hash ^= uint32(name[i])
+		hash *= 16777619
+	}
+	return hash
+}
+
+// get returns the value of the directory called name, or the zero value
+func (c *children[V]) get(name string) V {
+	var zero V
+	hash := hashName(name)
+	for shift := 0; c != nil; shift += hashBits {
+		if shift >= hashSize {
+			return c.getCollision(name)
+		}
+		bit := uint32(1) << ((hash >> shift) & (1<<hashBits - 1))
+		if c.bitmap&bit == 0 {
+			return zero
+		}
+		entry := c.entries[bits.OnesCount32(c.bitmap&(bit-1))]
+		if entry.next == nil {
+			if entry.name == name {
+				return entry.value
+			}
+			return zero
+		}
+		c = entry.next
+	}
+	return zero
+}
+
+// getCollision is a helper function for get that searches the names whose hashes are equal
+func (c *children[V]) getCollision(name string) V {
+	var zero V
+	for _, entry := range c.entries {
+		if entry.name == name {
+			return entry.value
+		}
+	}
+	return zero
+}
+
+// with returns a copy of the map where name maps to value, or where name is removed if value is the zero value. nil is
+// returned for the empty map.
+func (c *children[V]) with(name string, value V) *children[V] {
+	return c.withHash(hashName(name), 0, name, value)
+}
+
+// withHash is a helper function for with that changes the level of the map using the hash bits from shift
+func (c *children[V]) withHash(hash uint32, shift int, name string, value V) *children[V] {
+	var zero V
+	if c == nil {
+		c = &children[V]{}
+	}
+	if shift >= hashSize {
+		return c.withCollision(name, value)
+	}
+
+	bit := uint32(1) << ((hash >> shift) & (1<<hashBits - 1))
+	position := bits.OnesCount32(c.bitmap & (bit - 1))
+	copied := &children[V]{bitmap: c.bitmap, entries: slices.Clone(c.entries)}
+
+	if c.bitmap&bit == 0 {
+		if value == zero {
+			return c.orNil()
+		}
+		copied.bitmap |= bit
+		copied.entries = slices.Insert(copied.entries, position, childEntry[V]{name: name, value: value})
+		return copied
+	}
+
+	entry := c.entries[position]
+	switch {
+	case entry.next != nil:
+		entry.next = entry.next.withHash(hash, shift+hashBits, name, value)
+	case entry.name == name:
+		entry.value = value
+	case value != zero:
+		// the slot is taken by another name, so both move down a level
+		next := (*children[V])(nil).withHash(hashName(entry.name), shift+hashBits, entry.name, entry.value)
+		entry = childEntry[V]{next: next.withHash(hash, shift+hashBits, name, value)}
+	}
+
+	if entry.next == nil && entry.value == zero {
+		copied.bitmap &^= bit
+		copied.entries = slices.Delete(copied.entries, position, position+1)
+	} else {
+		copied.entries[position] = entry
+	}
+	return copied.orNil()
+}
+
+// withCollision is a helper function for withHash that changes the names whose hashes are equal
+func (c *children[V]) withCollision(name string, value V) *children[V] {
+	var zero V
+	copied := &children[V]{entries: slices.Clone(c.entries)}
+	for i, entry := range copied.entries {
+		if entry.name != name {
+			continue
+		}
+		if value == zero {
+			copied.entries = slices.Delete(copied.entries, i, i+1)
+		} else {
+			copied.entries[i].value = value
+		}
+		return copied.orNil()
+	}
+	if value != zero {
+		copied.entries = append(copied.entries, childEntry[V]{name: name, value: value})
+	}
+	return copied.orNil()
+}
+
+// orNil returns c, or nil if c is empty
+func (c *children[V]) orNil() *children[V] {
+	if len(c.entries) == 0 {
+		return nil
+	}
+	return c
+}
+
+// each calls fn for every name in the map and its value, in no particular order
+func (c *children[V]) each(fn func(name string, value V)) {
+	if c == nil {
+		return
+	}
+	for _, entry := range c.entries {
+		if entry.next != nil {
+			entry.next.each(fn)
+		} else {
+			fn(entry.name, entry.value)
+		}
+	}
+}
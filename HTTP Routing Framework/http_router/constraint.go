@@ -0,0 +1,81 @@
+/*****************************************************************************
+ * constraint.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// captureTypes maps the type names usable in typed captures such as `:id<int>` to the check a directory must pass
+var captureTypes = map[string]func(value string) bool{
+	"int": func(value string) bool {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	},
+	"uint": func(value string) bool {
+		_, err := strconv.ParseUint(value, 10, 64)
+		return err == nil
+	},
+	"uuid":  regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+	"alpha": regexp.MustCompile(`^[a-zA-Z]+$`).MatchString,
+	"alnum": regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString,
+}
+
+// parseCapture splits a capture directory such as `:id<int>` or `:slug{[a-z-]+}` into its name and its constraint,
+// which is "" for a capture that matches any directory
+func parseCapture(segment string) (name string, constraint string) {
+	name = segment[1:]
+	if i := strings.IndexAny(name, "<{"); i >= 0 {
+		return name[:i], name[i:]
+	}
+	return name, ""
+}
+
+// captureName returns the name a capture or catch-all directory stores its value under
+func captureName(segment string) string {
+	name, _ := parseCapture(segment)
+	return name
+}
+
+// captureConstraint returns the constraint of a capture directory, or "" if it has none
+func captureConstraint(segment string) string {
+	_, constraint := parseCapture(segment)
+	return constraint
+}
+
+// compileConstraint returns the check a directory must pass to be captured under constraint. A constraint is either
+// a type name in angle brackets, e.g. `<int>`, or a regular expression in braces, e.g. `{[a-z-]+}`, which must match
+// the whole directory. Invalid constraints panic, as they are mistakes in the pattern given to AddRoute.
+func compileConstraint(constraint string) func(value string) bool {
+	switch {
+	case constraint == "":
+		return nil
+	case strings.HasPrefix(constraint, "<") && strings.HasSuffix(constraint, ">"):
+		check, ok := captureTypes[constraint[1:len(constraint)-1]]
+		if !ok {
+			panic("http_router: unknown capture type " + constraint)
+		}
+		return check
+	case strings.HasPrefix(constraint, "{") && strings.HasSuffix(constraint, "}"):
+		expression, err := regexp.Compile("^(?:" + constraint[1:len(constraint)-1] + ")$")
+		if err != nil {
+			panic("http_router: invalid capture expression " + constraint + ": " + err.Error())
+		}
+		return expression.MatchString
+	default:
+		panic("http_router: invalid capture constraint " + constraint)
+	}
+}
+
+// matchesConstraint is a helper function for IsSameDynamicPattern that checks a request directory against the
+// constraint of a capture directory
+func matchesConstraint(segment string, value string) bool {
+	check := compileConstraint(captureConstraint(segment))
+	return check == nil || check(value)
+}
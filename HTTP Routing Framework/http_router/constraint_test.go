@@ -0,0 +1,60 @@
+/******************************************************************************
+ *  constraint_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for typed and regular expression constrained captures.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestConstraintFallThrough checks that a request failing a constraint falls through to the next candidate route.
+func TestConstraintFallThrough(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:name", echoParams)
+	router.AddRoute(httpGet, "/users/:id<int>", echoParams)
+	router.AddRoute(httpGet, "/users/:uid<uuid>", echoParams)
+	router.AddRoute(httpGet, "/posts/:slug{[a-z-]+}", echoParams)
+
+	tests := map[string]string{
+		"/users/42":    "id=42",
+		"/users/cesar": "name=cesar",
+		"/users/123e4567-e89b-12d3-a456-426614174000": "uid=123e4567-e89b-12d3-a456-426614174000",
+		"/posts/hello-world":                          "slug=hello-world",
+		"/posts/Hello":                                "404 page not found\n",
+	}
+	for path, expected := range tests {
+		if body := serveBody(router, httpGet, path); body != expected {
+			t.Errorf("Test failed: Expected %q for %s and received %q.", expected, path, body)
+		}
+	}
+}
+
+// TestConstraintDistinctRoutes checks that differently constrained captures are distinct routes, while captures with
+// the same constraint still overwrite each other.
+func TestConstraintDistinctRoutes(t *testing.T) {
+	if IsExistingPath("users/:id<int>", "users/:name") {
+		t.Errorf("Test failed: Expected a constrained and an unconstrained capture to be distinct routes.")
+	}
+	if !IsExistingPath("users/:id<int>/posts", "users/:user<int>/posts") {
+		t.Errorf("Test failed: Expected captures with the same constraint to be the same route.")
+	}
+
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:name", echoParams)
+	router.AddRoute(httpGet, "/users/:id<int>", echoParams)
+	router.AddRoute(httpGet, "/users/:user<int>", echoParams)
+	if len(router.Routes()) != 2 {
+		t.Fatalf("Test failed: Expected 2 routes and found %d.", len(router.Routes()))
+	}
+	if body := serveBody(router, httpGet, "/users/7"); body != "user=7" {
+		t.Errorf("Test failed: Expected %s and received %s.", "user=7", body)
+	}
+}
@@ -0,0 +1,202 @@
+/*****************************************************************************
+ * context.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Context bundles a request with its response writer, with helpers for the steps most handlers repeat: reading
+// captured and query values, binding the request body, and writing JSON or text responses. Handlers taking a Context
+// are added as plain http.HandlerFunc routes through HandleContext, so everything else, such as middlewares, sees a
+// plain handler.
+type Context struct {
+	Response http.ResponseWriter
+	Request  *http.Request
+	query    url.Values // query values, parsed on first use
+}
+
+// maxMemory is the number of bytes of a multipart form BindForm keeps in memory, the rest is stored on disk
+const maxMemory = 32 << 20
+
+// NewContext creates a context for a request and its response writer, so that plain handlers can use its helpers
+func NewContext(response http.ResponseWriter, request *http.Request) *Context {
+	return &Context{Response: response, Request: request}
+}
+
+// HandleContext adapts a handler taking a Context into an http.HandlerFunc that can be added with AddRoute, e.g.
+//
+//	router.AddRoute("GET", "/users/:user", HandleContext(func(ctx *Context) {
+//		ctx.String(http.StatusOK, "hello %s", ctx.Param("user"))
+//	}))
+func HandleContext(handler func(ctx *Context)) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		handler(NewContext(response, request))
+	}
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// Param returns the first value captured under name by the matched route, or "" if there is none
+func (ctx *Context) Param(name string) string {
+	return Param(ctx.Request, name)
+}
+
+// Query returns the first value of the query parameter called name, or "" if there is none
+func (ctx *Context) Query(name string) string {
+	return ctx.queryValues().Get(name)
+}
+
+// queryValues returns the query values of the request, parsing them on first use
+func (ctx *Context) queryValues() url.Values {
+	if ctx.query == nil {
+		ctx.query = ctx.Request.URL.Query()
+	}
+	return ctx.query
+}
+
+// BindJSON decodes the JSON request body into target and validates it. A malformed body, or one holding more than a
+// single JSON value, gives a BindError, and a value failing the rules of its validate tags a ValidationError.
+func (ctx *Context) BindJSON(target any) error {
+	if err := decodeJSON(ctx.Request.Body, target); err != nil {
+		return err
+	}
+	return Validate(target)
+}
+
+// decodeJSON decodes the single JSON value body holds into target
+func decodeJSON(body io.Reader, target any) error {
+	decoder := json.NewDecoder(body)
+	if err := decoder.Decode(target); err != nil {
+		return &BindError{Err: err}
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return &BindError{Err: errors.New("unexpected data after the JSON value")}
+	}
+	return nil
+}
+
+// BindForm sets the fields of the struct target points to from the form values of the request, URL-encoded or
+// multipart, and its query, by their `form` tag, e.g.
+//
+//	Page int `form:"page"`
+//
+// Fields are bound as bindValues binds them, and values that can't be converted to their field give a BindError. The
+// bound struct is then validated, and a ValidationError lists the fields failing the rules of their validate tags.
+func (ctx *Context) BindForm(target any) error {
+	var err error
+	if mediaType, _, _ := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		err = ctx.Request.ParseMultipartForm(maxMemory)
+	} else {
+		err = ctx.Request.ParseForm()
+	}
+	if err != nil {
+		return &BindError{Err: err}
+	}
+	if err := bindValues(target, "form", func(name string) []string {
+		return ctx.Request.Form[name]
+	}); err != nil {
+		return err
+	}
+	return Validate(target)
+}
+
+// Bind sets the fields of the struct target points to from every part of the request. A request body is decoded as
+// JSON, then the fields tagged `path` are set from the values captured by the route, those tagged `query` from the
+// query, and those tagged `header` from the request headers, e.g.
+//
+//	type UpdateUser struct {
+//		User   string `path:"user"`
+//		DryRun bool   `query:"dry_run"`
+//		Token  string `header:"X-Token"`
+//		Email  string `json:"email"`
+//	}
+//
+// Tagged fields are bound as bindValues binds them, overriding any value the body gave them. A body whose content type
+// is given and isn't JSON gives a StatusError with a 415, and a body or value that can't be bound gives a BindError.
+// Finally the bound struct is validated, so that a ValidationError lists the fields failing their validate tags.
+func (ctx *Context) Bind(target any) error {
+	if err := ctx.bindBody(target); err != nil {
+		return err
+	}
+	if err := bindValues(target, "path", func(name string) []string {
+		return Params(ctx.Request)[name]
+	}); err != nil {
+		return err
+	}
+	if err := bindValues(target, "query", func(name string) []string {
+		return ctx.queryValues()[name]
+	}); err != nil {
+		return err
+	}
+	if err := bindValues(target, "header", ctx.Request.Header.Values); err != nil {
+		return err
+	}
+	return Validate(target)
+}
+
+// bindBody is a helper function for Bind that decodes the request body into target, if the request has one
+func (ctx *Context) bindBody(target any) error {
+	if ctx.Request.Body == nil {
+		return nil
+	}
+	body := bufio.NewReader(ctx.Request.Body)
+	if _, err := body.Peek(1); errors.Is(err, io.EOF) {
+		return nil
+	} else if err != nil {
+		return &BindError{Err: err}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return NewStatusError(http.StatusUnsupportedMediaType, "unsupported content type "+mediaType)
+	}
+	return decodeJSON(body, target)
+}
+
+// JSON writes value as a JSON response with status. Nothing is written if value can't be encoded.
+func (ctx *Context) JSON(status int, value any) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return ctx.writeJSON(status, body)
+}
+
+// writeJSON writes an encoded JSON body as a response with status
+func (ctx *Context) writeJSON(status int, body []byte) error {
+	ctx.Response.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.Response.WriteHeader(status)
+	_, err := ctx.Response.Write(append(body, '\n'))
+	return err
+}
+
+// String writes a plain text response with status, formatting its body as fmt.Sprintf does
+func (ctx *Context) String(status int, format string, args ...any) error {
+	ctx.Response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	ctx.Response.WriteHeader(status)
+	_, err := fmt.Fprintf(ctx.Response, format, args...)
+	return err
+}
+
+// Status writes a response with status and no body
+func (ctx *Context) Status(status int) {
+	ctx.Response.WriteHeader(status)
+}
+
+// Redirect redirects the request to location with status, which should be a 3xx status
+func (ctx *Context) Redirect(status int, location string) {
+	http.Redirect(ctx.Response, ctx.Request, location, status)
+}
@@ -0,0 +1,160 @@
+/******************************************************************************
+ *  context_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for the Context handler helpers for binding and writing responses.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// serveContext sends a single request with the given body and content type to a router holding handler at pattern
+func serveContext(pattern string, handler func(ctx *Context), method string, path string, body string,
+	contentType string) *httptest.ResponseRecorder {
+	router := NewRouter()
+	router.AddRoute(method, pattern, HandleContext(handler))
+
+	request := httptest.NewRequest(method, "http://localhost:8080"+path, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestContextParamAndQuery checks that a context handler reads path captures and the untouched query string.
+func TestContextParamAndQuery(t *testing.T) {
+	recorder := serveContext("/users/:user", func(ctx *Context) {
+		ctx.String(http.StatusOK, "%s page %s", ctx.Param("user"), ctx.Query("page"))
+	}, httpGet, "/users/bob?page=2", "", "")
+
+	if body := recorder.Body.String(); body != "bob page 2" {
+		t.Errorf("Test failed: Expected %s and received %s.", "bob page 2", body)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "text/plain; charset=utf-8" {
+		t.Errorf("Test failed: Expected a plain text response and received %s.", contentType)
+	}
+}
+
+// TestContextBindJSON checks that a JSON body is decoded into a value and written back as JSON, while a malformed
+// body or one with trailing data gives a BindError.
+func TestContextBindJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	handler := func(ctx *Context) {
+		var in user
+		var bindErr *BindError
+		if err := ctx.BindJSON(&in); errors.As(err, &bindErr) {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		ctx.JSON(http.StatusCreated, in)
+	}
+
+	recorder := serveContext("/users", handler, httpPost, "/users", `{"name":"bob","age":7}`, "application/json")
+	if recorder.Code != http.StatusCreated || recorder.Body.String() != "{\"name\":\"bob\",\"age\":7}\n" {
+		t.Errorf("Test failed: Expected a 201 echoing the user and received %d %s.", recorder.Code, recorder.Body)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("Test failed: Expected a JSON response and received %s.", contentType)
+	}
+
+	for _, body := range []string{`{"name":`, `{"name":"bob"} {}`, `{"age":"seven"}`} {
+		recorder := serveContext("/users", handler, httpPost, "/users", body, "application/json")
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Test failed: Expected a 400 for %s and received %d.", body, recorder.Code)
+		}
+	}
+}
+
+// TestContextBindTagMistake checks that a validate tag naming an unknown rule is returned by BindJSON as a server error
+// instead of panicking in the handler.
+func TestContextBindTagMistake(t *testing.T) {
+	handler := func(ctx *Context) {
+		var in struct {
+			Name string `json:"name" validate:"requird"`
+		}
+		if err := ctx.BindJSON(&in); err != nil {
+			ctx.Status(ErrorStatus(err))
+			return
+		}
+		ctx.Status(http.StatusCreated)
+	}
+
+	recorder := serveContext("/users", handler, httpPost, "/users", `{"name":"bob"}`, "application/json")
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Test failed: Expected %d and received %d.", http.StatusInternalServerError, recorder.Code)
+	}
+}
+
+// TestContextBindForm checks that URL-encoded and multipart forms are bound by their form tags, together with the
+// query, and that a value of the wrong type names its field.
+func TestContextBindForm(t *testing.T) {
+	type search struct {
+		Term  string   `form:"q"`
+		Page  int      `form:"page"`
+		Tags  []string `form:"tag"`
+		Debug *bool    `form:"debug"`
+	}
+	handler := func(ctx *Context) {
+		var in search
+		if err := ctx.BindForm(&in); err != nil {
+			ctx.String(http.StatusBadRequest, "%v", err)
+			return
+		}
+		ctx.String(http.StatusOK, "%s %d %v %v", in.Term, in.Page, in.Tags, *in.Debug)
+	}
+
+	recorder := serveContext("/search", handler, httpPost, "/search?page=3", "q=go&tag=a&tag=b&debug=true",
+		"application/x-www-form-urlencoded")
+	if body := recorder.Body.String(); body != "go 3 [a b] true" {
+		t.Errorf("Test failed: Expected %s and received %s.", "go 3 [a b] true", body)
+	}
+
+	multipart := "--x\r\nContent-Disposition: form-data; name=\"q\"\r\n\r\nrouters\r\n" +
+		"--x\r\nContent-Disposition: form-data; name=\"debug\"\r\n\r\nfalse\r\n--x--\r\n"
+	recorder = serveContext("/search", handler, httpPost, "/search", multipart, "multipart/form-data; boundary=x")
+	if body := recorder.Body.String(); body != "routers 0 [] false" {
+		t.Errorf("Test failed: Expected %s and received %s.", "routers 0 [] false", body)
+	}
+
+	recorder = serveContext("/search", handler, httpPost, "/search?page=two", "", "")
+	if expected := "http_router: invalid value for page: "; !strings.HasPrefix(recorder.Body.String(), expected) {
+		t.Errorf("Test failed: Expected %s and received %s.", expected, recorder.Body)
+	}
+}
+
+// TestContextRedirect checks that Redirect writes the status and location, and Status writes an empty response.
+func TestContextRedirect(t *testing.T) {
+	recorder := serveContext("/old", func(ctx *Context) {
+		ctx.Redirect(http.StatusPermanentRedirect, "/new")
+	}, httpPost, "/old", "", "")
+	if recorder.Code != http.StatusPermanentRedirect || recorder.Header().Get("Location") != "/new" {
+		t.Errorf("Test failed: Expected a 308 to /new and received %d %s.", recorder.Code,
+			recorder.Header().Get("Location"))
+	}
+
+	recorder = serveContext("/empty", func(ctx *Context) { ctx.Status(http.StatusNoContent) }, httpGet, "/empty", "",
+		"")
+	if recorder.Code != http.StatusNoContent || recorder.Body.Len() != 0 {
+		t.Errorf("Test failed: Expected an empty 204 and received %d %s.", recorder.Code, recorder.Body)
+	}
+}
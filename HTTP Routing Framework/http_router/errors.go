@@ -0,0 +1,67 @@
+/*****************************************************************************
+ * errors.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// StatusError is an error to be answered with a particular status, such as a 404 for a record a handler couldn't find
+type StatusError struct {
+	Status int
+	Err    error // underlying error, or nil to describe the error by its status
+}
+
+// NewStatusError creates an error to be answered with status, described by message
+func NewStatusError(status int, message string) *StatusError {
+	return &StatusError{Status: status, Err: errors.New(message)}
+}
+
+// Error returns the message of the underlying error, or the text of the status if there is none
+func (err *StatusError) Error() string {
+	if err.Err == nil {
+		return strings.ToLower(http.StatusText(err.Status))
+	}
+	return err.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (err *StatusError) Unwrap() error {
+	return err.Err
+}
+
+// Errors for the failures handlers most often answer. Handlers wrap them to describe the failure, e.g.
+// fmt.Errorf("no user %s: %w", id, ErrNotFound) is answered with a 404 showing its message.
+var (
+	ErrNotFound     = &StatusError{Status: http.StatusNotFound}
+	ErrConflict     = &StatusError{Status: http.StatusConflict}
+	ErrUnauthorized = &StatusError{Status: http.StatusUnauthorized}
+	ErrForbidden    = &StatusError{Status: http.StatusForbidden}
+)
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// ErrorStatus is the default ErrorMapper, returning the status a request that failed with err should be answered
+// with: the status of the first StatusError err wraps, 400 for a BindError, 422 for a ValidationError, and 500 for
+// any other error
+func ErrorStatus(err error) int {
+	var statusErr *StatusError
+	var bindErr *BindError
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Status
+	case errors.As(err, &bindErr):
+		return http.StatusBadRequest
+	case errors.As(err, &validationErr):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
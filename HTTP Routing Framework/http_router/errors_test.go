@@ -0,0 +1,45 @@
+/******************************************************************************
+ *  errors_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for mapping the errors handlers fail with to response statuses.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestErrorStatus checks that wrapped status, bind and validation errors keep their status, and other errors,
+// including the router's own route conflicts, are server errors.
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{NewStatusError(http.StatusConflict, "taken"), http.StatusConflict},
+		{fmt.Errorf("saving: %w", &StatusError{Status: http.StatusForbidden}), http.StatusForbidden},
+		{fmt.Errorf("no user bob: %w", ErrNotFound), http.StatusNotFound},
+		{&BindError{Field: "page", Err: errors.New("bad")}, http.StatusBadRequest},
+		{&ConflictError{Method: httpGet, Pattern: "a/:b", Existing: "a/:c"}, http.StatusInternalServerError},
+		{&ValidationError{Fields: []FieldError{{"name", "required", "is required"}}}, http.StatusUnprocessableEntity},
+		{errors.New("unknown"), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		if status := ErrorStatus(test.err); status != test.expected {
+			t.Errorf("Test failed: Expected %d for %v and received %d.", test.expected, test.err, status)
+		}
+	}
+
+	if message := (&StatusError{Status: http.StatusForbidden}).Error(); message != "forbidden" {
+		t.Errorf("Test failed: Expected forbidden and received %s.", message)
+	}
+}
@@ -0,0 +1,186 @@
+/*****************************************************************************
+ * fallback.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrorHandler writes the response for a request that failed with the given status, err may be nil
+type ErrorHandler func(response http.ResponseWriter, request *http.Request, status int, err error)
+
+// Fallbacks are the handlers answering requests that have no route, and requests that failed. They can be set on the
+// router and on groups. A request uses the innermost group of its path that sets a fallback for it, where Error is a
+// fallback for every failure, then the router's, and finally TextError.
+type Fallbacks struct {
+	// NotFound answers requests whose path has no route
+	NotFound http.Handler
+	// MethodNotAllowed answers requests whose path only has routes for other methods, its Allow header is already set
+	MethodNotAllowed http.Handler
+	// Error writes the response for any other failed request
+	Error ErrorHandler
+}
+
+// Problem is a problem details body as defined by RFC 9457, with the invalid fields of a failed validation as an
+// extension member
+type Problem struct {
+	Type     string       `json:"type,omitempty"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// TextError is the default ErrorHandler, answering with a plain text body such as "404 page not found". The message
+// of err is only shown to the client for 4xx statuses, so that server errors don't leak internal details.
+func TextError(response http.ResponseWriter, request *http.Request, status int, err error) {
+	message := strings.ToLower(http.StatusText(status))
+	if status == http.StatusNotFound {
+		message = "page not found"
+	}
+	if err != nil && status < http.StatusInternalServerError {
+		message = err.Error()
+	}
+	http.Error(response, fmt.Sprintf("%d %s", status, message), status)
+}
+
+// ProblemJSON is an ErrorHandler answering with an application/problem+json body as defined by RFC 9457. As with
+// TextError, the message of err is only given as the detail for 4xx statuses. The fields of a ValidationError are
+// listed under "errors".
+func ProblemJSON(response http.ResponseWriter, request *http.Request, status int, err error) {
+	problem := Problem{
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: request.URL.Path,
+	}
+	if err != nil && status < http.StatusInternalServerError {
+		problem.Detail = err.Error()
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Fields
+	}
+	WriteProblem(response, problem)
+}
+
+// WriteProblem writes problem as an application/problem+json response with its status
+func WriteProblem(response http.ResponseWriter, problem Problem) {
+	body, err := json.Marshal(problem)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response.Header().Set("Content-Type", "application/problem+json")
+	response.Header().Set("X-Content-Type-Options", "nosniff")
+	response.WriteHeader(problem.Status)
+	response.Write(body)
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// fallbacksFor returns the fallbacks of the groups whose prefix the request directories start with, and whose host,
+// if any, matches the request host labels, from the longest prefix to the shortest, followed by the router's own. Of
+// groups with the same prefix, those scoped to a host come first.
+func (router *HTTPRouter) fallbacksFor(table *routeTable, hostLabels []string, requestSegments []string) []*Fallbacks {
+	var groups []*Group
+	for _, group := range table.groups {
+		if (group.host == nil || group.host.matches(hostLabels)) &&
+			hasPrefixSegments(requestSegments, splitPattern(group.prefix)) {
+			groups = append(groups, group)
+		}
+	}
+	// every prefix matched the same request, so the longer prefix is the more nested one
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].prefix) != len(groups[j].prefix) {
+			return len(groups[i].prefix) > len(groups[j].prefix)
+		}
+		return groups[i].host != nil && groups[j].host == nil
+	})
+
+	fallbacks := make([]*Fallbacks, 0, len(groups)+1)
+	for _, group := range groups {
+		fallbacks = append(fallbacks, &group.Fallbacks)
+	}
+	return append(fallbacks, &router.Fallbacks)
+}
+
+// hasPrefixSegments reports whether the request directories start with the prefix directories
+func hasPrefixSegments(requestSegments []string, prefixSegments []string) bool {
+	if len(prefixSegments) > len(requestSegments) {
+		return false
+	}
+	for i := range prefixSegments {
+		if requestSegments[i] != prefixSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// notFoundHandler returns the handler answering a request whose path has no route
+func (router *HTTPRouter) notFoundHandler(table *routeTable, hostLabels []string,
+	requestSegments []string) http.Handler {
+	for _, fallbacks := range router.fallbacksFor(table, hostLabels, requestSegments) {
+		if fallbacks.NotFound != nil {
+			return fallbacks.NotFound
+		}
+		if fallbacks.Error != nil {
+			return errorHandler(fallbacks.Error, http.StatusNotFound)
+		}
+	}
+	return errorHandler(TextError, http.StatusNotFound)
+}
+
+// methodNotAllowedHandler returns the handler answering a request whose path only has routes for other methods, after
+// setting its Allow header to the allowed methods
+func (router *HTTPRouter) methodNotAllowedHandler(table *routeTable, hostLabels []string, requestSegments []string,
+	allowed []string) http.Handler {
+	handler := errorHandler(TextError, http.StatusMethodNotAllowed)
+	for _, fallbacks := range router.fallbacksFor(table, hostLabels, requestSegments) {
+		if fallbacks.MethodNotAllowed != nil {
+			handler = fallbacks.MethodNotAllowed
+			break
+		}
+		if fallbacks.Error != nil {
+			handler = errorHandler(fallbacks.Error, http.StatusMethodNotAllowed)
+			break
+		}
+	}
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Allow", strings.Join(allowed, ", "))
+		handler.ServeHTTP(response, request)
+	})
+}
+
+// errorHandler returns a handler answering with status through handleError
+func errorHandler(handleError ErrorHandler, status int) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		handleError(response, request, status, nil)
+	})
+}
+
+// WriteError writes the response for a request that failed with status through the Error fallback of the innermost
+// group of the request's path that sets one, or of the router. Handlers can call it to answer errors consistently.
+func (router *HTTPRouter) WriteError(response http.ResponseWriter, request *http.Request, status int, err error) {
+	table := router.loadTable()
+	requestSegments := splitPattern(trimPattern(request.URL.Path))
+	for _, fallbacks := range router.fallbacksFor(table, table.requestHostLabels(request), requestSegments) {
+		if fallbacks.Error != nil {
+			fallbacks.Error(response, request, status, err)
+			return
+		}
+	}
+	TextError(response, request, status, err)
+}
@@ -0,0 +1,73 @@
+/******************************************************************************
+ *  fallback_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for the NotFound, MethodNotAllowed and Error fallbacks of routers
+ *    and groups.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestProblemJSON checks that the ProblemJSON error handler answers a 404 with an RFC 9457 body.
+func TestProblemJSON(t *testing.T) {
+	router := NewRouter()
+	router.Error = ProblemJSON
+
+	request := httptest.NewRequest(httpGet, "http://localhost:8080/missing", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	if response.StatusCode != httpNotFound {
+		t.Errorf("Test failed: Router gave non-404 status code: %d", response.StatusCode)
+	}
+	if contentType := response.Header.Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Test failed: Expected an application/problem+json body and received %s.", contentType)
+	}
+
+	var problem Problem
+	if err := json.NewDecoder(response.Body).Decode(&problem); err != nil {
+		t.Fatalf("Test failed: Could not decode the problem: %v", err)
+	}
+	if problem.Status != httpNotFound || problem.Title != "Not Found" || problem.Instance != "/missing" {
+		t.Errorf("Test failed: Received unexpected problem %+v.", problem)
+	}
+}
+
+// TestGroupFallbacks checks that requests under a group prefix use the fallbacks of the innermost group setting them.
+func TestGroupFallbacks(t *testing.T) {
+	router := NewRouter()
+	router.NotFound = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.WriteHeader(httpNotFound)
+		response.Write([]byte("<h1>Not Found</h1>"))
+	})
+	api := router.Group("/api")
+	api.Error = ProblemJSON
+	v1 := api.Group("/v1")
+	v1.MethodNotAllowed = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.WriteHeader(http.StatusMethodNotAllowed)
+		response.Write([]byte(response.Header().Get("Allow")))
+	})
+	v1.AddRoute(httpGet, "/users/:user", echoParams)
+
+	if body := serveBody(router, httpGet, "/about"); body != "<h1>Not Found</h1>" {
+		t.Errorf("Test failed: Expected the router NotFound handler and received %s.", body)
+	}
+	if body := serveBody(router, httpGet, "/api/v1/missing"); body != `{"title":"Not Found","status":404,"instance":"/api/v1/missing"}` {
+		t.Errorf("Test failed: Expected the api group Error handler and received %s.", body)
+	}
+	if body := serveBody(router, httpPost, "/api/v1/users/cesar"); body != "GET, HEAD, OPTIONS" {
+		t.Errorf("Test failed: Expected the v1 group MethodNotAllowed handler and received %s.", body)
+	}
+}
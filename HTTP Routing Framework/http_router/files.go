@@ -0,0 +1,313 @@
+/*****************************************************************************
+ * files.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileServer serves the files of a file system, such as an embed.FS or os.DirFS. Requests for a directory are served
+// its first existing index file, or a listing of its entries if ListDirectories is set. Responses carry an ETag and a
+// Last-Modified header, conditional and byte-range requests are answered as http.ServeContent answers them, and
+// clients accepting gzip are served the precompressed `.gz` sibling of a file if there is one. Paths leaving the file
+// system with `..` are rejected with a 400. A FileServer must not be copied after first use.
+type FileServer struct {
+	FS fs.FS
+	// IndexFiles are the names of the files served for a directory, in order of preference
+	IndexFiles []string
+	// ListDirectories answers requests for directories without an index file with a listing of their entries, instead
+	// of a 404
+	ListDirectories bool
+	// Precompressed serves the `.gz` sibling of a file, if there is one, to clients accepting gzip
+	Precompressed bool
+	// Error writes the response for failed requests. When nil, it is TextError for a FileServer used as a handler,
+	// and the router's Error fallback for one added with ServeFiles.
+	Error ErrorHandler
+
+	hashes sync.Map // content hashes of the files without a modification time, keyed by their hashKey
+}
+
+// hashKey identifies a file whose content hash is kept by a FileServer
+type hashKey struct {
+	name string
+	size int64
+}
+
+// NewFileServer creates a file server for fsys serving `index.html` for directories, without directory listings,
+// and with precompressed files
+func NewFileServer(fsys fs.FS) *FileServer {
+	return &FileServer{
+		FS:            fsys,
+		IndexFiles:    []string{"index.html"},
+		Precompressed: true,
+	}
+}
+
+// ServeFS serves the files of fsys to GET and HEAD requests under prefix with a file server created by NewFileServer,
+// e.g. after ServeFS("/static", fsys) a request for `/static/css/site.css` is served the file `css/site.css`
+func (router *HTTPRouter) ServeFS(prefix string, fsys fs.FS, options ...RouteOption) {
+	router.ServeFiles(prefix, NewFileServer(fsys), options...)
+}
+
+// ServeFiles serves files with server to GET and HEAD requests under prefix. It adds a route for prefix followed by
+// a `*filepath` catch-all naming the file, and a route for prefix with a trailing '/' serving the root directory.
+func (router *HTTPRouter) ServeFiles(prefix string, server *FileServer, options ...RouteOption) {
+	handler := func(response http.ResponseWriter, request *http.Request) {
+		writeError := server.Error
+		if writeError == nil {
+			writeError = router.WriteError
+		}
+		server.serve(response, request, Param(request, "filepath"), writeError)
+	}
+
+	prefix = trimPattern(prefix)
+	root := "/"
+	if prefix != "" {
+		root = "/" + prefix + "/"
+	}
+	router.AddRoute(http.MethodGet, root, handler, options...)
+	router.AddRoute(http.MethodGet, joinPattern(prefix, "*filepath"), handler, options...)
+}
+
+// ServeFS serves the files of fsys under prefix, with the group prefix prepended, as HTTPRouter.ServeFS does
+func (group *Group) ServeFS(prefix string, fsys fs.FS, options ...RouteOption) {
+	group.ServeFiles(prefix, NewFileServer(fsys), options...)
+}
+
+// ServeFiles serves files with server under prefix, with the group prefix prepended, as HTTPRouter.ServeFiles does,
+// wrapped in the group middlewares and scoped to the group's host
+func (group *Group) ServeFiles(prefix string, server *FileServer, options ...RouteOption) {
+	group.router.ServeFiles(group.fullPattern(prefix), server, group.routeOptions(options)...)
+}
+
+// ServeHTTP serves the file named by the request path, so that a FileServer can be mounted or used on its own
+func (server *FileServer) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	writeError := server.Error
+	if writeError == nil {
+		writeError = TextError
+	}
+	server.serve(response, request, request.URL.Path, writeError)
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// errInvalidPath is the error requests for paths leaving the file system fail with
+var errInvalidPath = errors.New("invalid URL path")
+
+// serve answers request with the file or directory called name, failing through writeError
+func (server *FileServer) serve(response http.ResponseWriter, request *http.Request, name string,
+	writeError ErrorHandler) {
+	name, ok := fileName(name)
+	if !ok {
+		writeError(response, request, http.StatusBadRequest, errInvalidPath)
+		return
+	}
+
+	info, err := fs.Stat(server.FS, name)
+	if err != nil {
+		writeError(response, request, fileErrorStatus(err), nil)
+		return
+	}
+	if !info.IsDir() {
+		server.serveFile(response, request, name, info, writeError)
+		return
+	}
+
+	// a directory is served at a path ending with '/', so that relative links in it resolve inside it. The redirect
+	// is relative, as the request path may have had a mount prefix stripped.
+	if !strings.HasSuffix(request.URL.Path, "/") {
+		location := url.URL{Path: path.Base(request.URL.Path) + "/", RawQuery: request.URL.RawQuery}
+		response.Header().Set("Location", location.String())
+		response.WriteHeader(http.StatusMovedPermanently)
+		return
+	}
+	for _, index := range server.IndexFiles {
+		indexName := path.Join(name, index)
+		if indexInfo, err := fs.Stat(server.FS, indexName); err == nil && indexInfo.Mode().IsRegular() {
+			server.serveFile(response, request, indexName, indexInfo, writeError)
+			return
+		}
+	}
+	if !server.ListDirectories {
+		writeError(response, request, http.StatusNotFound, nil)
+		return
+	}
+	server.serveListing(response, request, name, writeError)
+}
+
+// fileName returns the file system name for a file path taken from a request, or false if the path leaves the file
+// system. The root directory is named ".".
+func fileName(filePath string) (string, bool) {
+	name := strings.Trim(filePath, "/")
+	if name == "" {
+		return ".", true
+	}
+	return name, fs.ValidPath(name)
+}
+
+// fileErrorStatus returns the status answering a request whose file could not be opened with err
+func fileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// serveFile answers request with the regular file called name, or its precompressed sibling
+func (server *FileServer) serveFile(response http.ResponseWriter, request *http.Request, name string,
+	info fs.FileInfo, writeError ErrorHandler) {
+	if !info.Mode().IsRegular() {
+		writeError(response, request, http.StatusNotFound, nil)
+		return
+	}
+
+	servedName, servedInfo := name, info
+	if server.Precompressed {
+		gzipInfo, err := fs.Stat(server.FS, name+".gz")
+		if err == nil && gzipInfo.Mode().IsRegular() {
+			response.Header().Add("Vary", "Accept-Encoding")
+			if acceptsGzip(request) {
+				servedName, servedInfo = name+".gz", gzipInfo
+				response.Header().Set("Content-Encoding", "gzip")
+				// the compressed content would be sniffed as gzip, so the type is taken from the name alone
+				if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+					response.Header().Set("Content-Type", contentType)
+				}
+			}
+		}
+	}
+
+	content, err := openSeeker(server.FS, servedName)
+	if err != nil {
+		writeError(response, request, fileErrorStatus(err), nil)
+		return
+	}
+	defer content.Close()
+
+	// ETag and Last-Modified let http.ServeContent answer conditional requests and If-Range checks
+	tag, err := server.fileETag(servedName, servedInfo, content)
+	if err != nil {
+		writeError(response, request, http.StatusInternalServerError, nil)
+		return
+	}
+	response.Header().Set("ETag", tag)
+	http.ServeContent(response, request, name, servedInfo.ModTime(), content)
+}
+
+// acceptsGzip reports whether the request accepts a gzip encoded response
+func acceptsGzip(request *http.Request) bool {
+	for _, header := range request.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(coding), ";")
+			if strings.EqualFold(strings.TrimSpace(name), "gzip") &&
+				strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fileETag returns a strong entity tag for the file called name, derived from its modification time and size. Files
+// without a modification time, such as those of an embed.FS, are tagged by a hash of their content instead, which is
+// read from content and rewound, since versions of the same size would otherwise share a tag. Such files are taken to
+// never change, so the hash is only computed once for each name and size. The precompressed sibling of a file has its
+// own tag, as it is a different representation.
+func (server *FileServer) fileETag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	tag := fmt.Sprintf(`"%x-%x`, info.ModTime().UnixNano(), info.Size())
+	if info.ModTime().IsZero() {
+		key := hashKey{name: name, size: info.Size()}
+		hash, ok := server.hashes.Load(key)
+		if !ok {
+			sum := sha256.New()
+			if _, err := io.Copy(sum, content); err != nil {
+				return "", err
+			}
+			if _, err := content.Seek(0, io.SeekStart); err != nil {
+				return "", err
+			}
+			hash, _ = server.hashes.LoadOrStore(key, sum.Sum(nil)[:16])
+		}
+		tag = fmt.Sprintf(`"%x`, hash)
+	}
+	if strings.HasSuffix(name, ".gz") {
+		tag += "-gz"
+	}
+	return tag + `"`, nil
+}
+
+// readSeekCloser is the content of a file that http.ServeContent can seek in for byte-range requests
+type readSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// openSeeker opens the file called name in fsys, reading it into memory if the file system's files can't seek
+func openSeeker(fsys fs.FS, name string) (readSeekCloser, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if seeker, ok := file.(readSeekCloser); ok {
+		return seeker, nil
+	}
+	defer file.Close()
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(body)}, nil
+}
+
+// nopCloser is a ReadSeeker with a Close method that does nothing
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+// serveListing answers request with an HTML listing of the entries of the directory called name
+func (server *FileServer) serveListing(response http.ResponseWriter, request *http.Request, name string,
+	writeError ErrorHandler) {
+	entries, err := fs.ReadDir(server.FS, name)
+	if err != nil {
+		writeError(response, request, fileErrorStatus(err), nil)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	response.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintln(response, "<!doctype html>\n<meta name=\"viewport\" content=\"width=device-width\">\n<pre>")
+	for _, entry := range entries {
+		entryName := entry.Name()
+		if entry.IsDir() {
+			entryName += "/"
+		}
+		link := url.URL{Path: "./" + entryName}
+		fmt.Fprintf(response, "<a href=\"%s\">%s</a>\n", html.EscapeString(link.String()), html.EscapeString(entryName))
+	}
+	fmt.Fprintln(response, "</pre>")
+}
@@ -0,0 +1,209 @@
+/******************************************************************************
+ *  files_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for serving files from an fs.FS.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// modified is the modification time of every file in newTestFS
+var modified = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestFS creates a file system with an index file, a stylesheet, a script with a precompressed sibling, and a
+// directory without an index file
+func newTestFS() fstest.MapFS {
+	file := func(data string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(data), ModTime: modified}
+	}
+	return fstest.MapFS{
+		"index.html":      file("<h1>home</h1>"),
+		"css/site.css":    file("body { color: black; }"),
+		"js/app.js":       file("console.log('app')"),
+		"js/app.js.gz":    file("gzipped app"),
+		"docs/readme.txt": file("read me"),
+		"docs/sub/a.txt":  file("a"),
+	}
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestServeFS checks that files are served with their type and validators, and that conditional and byte-range
+// requests are answered.
+func TestServeFS(t *testing.T) {
+	router := NewRouter()
+	router.ServeFS("/static/", newTestFS())
+
+	recorder := servePath(router, httpGet, "/static/css/site.css")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "body { color: black; }" {
+		t.Fatalf("Test failed: Expected the stylesheet and received %d %s.", recorder.Code, recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/css") {
+		t.Errorf("Test failed: Expected text/css and received %s.", contentType)
+	}
+	etag := recorder.Header().Get("ETag")
+	if etag == "" || recorder.Header().Get("Last-Modified") != modified.Format(http.TimeFormat) {
+		t.Errorf("Test failed: Expected validators and received %v.", recorder.Header())
+	}
+
+	tests := []struct {
+		headers []string
+		status  int
+		body    string
+	}{
+		{[]string{"If-None-Match", etag}, http.StatusNotModified, ""},
+		{[]string{"If-Modified-Since", modified.Format(http.TimeFormat)}, http.StatusNotModified, ""},
+		{[]string{"If-None-Match", `"other"`}, http.StatusOK, "body { color: black; }"},
+		{[]string{"Range", "bytes=0-3"}, http.StatusPartialContent, "body"},
+		{[]string{"Range", "bytes=0-3", "If-Range", `"other"`}, http.StatusOK, "body { color: black; }"},
+	}
+	for _, test := range tests {
+		recorder := servePath(router, httpGet, "/static/css/site.css", test.headers...)
+		if recorder.Code != test.status || recorder.Body.String() != test.body {
+			t.Errorf("Test failed: Expected %d %q for %v and received %d %q.", test.status, test.body, test.headers,
+				recorder.Code, recorder.Body.String())
+		}
+	}
+
+	if body := serveBody(router, "HEAD", "/static/css/site.css"); body != "" {
+		t.Errorf("Test failed: Expected an empty HEAD body and received %s.", body)
+	}
+}
+
+// TestServeFSDirectories checks that directories are redirected to their path with a trailing slash and served
+// their index file, a listing, or a 404.
+func TestServeFSDirectories(t *testing.T) {
+	router := NewRouter()
+	router.ServeFS("/static", newTestFS())
+
+	if body := servePath(router, httpGet, "/static/").Body.String(); body != "<h1>home</h1>" {
+		t.Errorf("Test failed: Expected the index file and received %s.", body)
+	}
+	for path, location := range map[string]string{"/static": "static/?page=2", "/static/docs": "docs/?page=2"} {
+		recorder := servePath(router, httpGet, path)
+		if recorder.Code != http.StatusMovedPermanently || recorder.Header().Get("Location") != location {
+			t.Errorf("Test failed: Expected a redirect to %s for %s and received %d %s.", location, path,
+				recorder.Code, recorder.Header().Get("Location"))
+		}
+	}
+	if recorder := servePath(router, httpGet, "/static/docs/"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Test failed: Expected a 404 without listings and received %d.", recorder.Code)
+	}
+
+	server := NewFileServer(newTestFS())
+	server.ListDirectories = true
+	router.ServeFiles("/listed", server)
+	body := servePath(router, httpGet, "/listed/docs/").Body.String()
+	if !strings.Contains(body, `<a href="./readme.txt">readme.txt</a>`) ||
+		!strings.Contains(body, `<a href="./sub/">sub/</a>`) {
+		t.Errorf("Test failed: Expected a listing and received %s.", body)
+	}
+}
+
+// TestServeFSPrecompressed checks that clients accepting gzip are served the precompressed sibling of a file.
+func TestServeFSPrecompressed(t *testing.T) {
+	router := NewRouter()
+	router.ServeFS("/static", newTestFS())
+
+	recorder := servePath(router, httpGet, "/static/js/app.js", "Accept-Encoding", "br, gzip;q=0.8")
+	if recorder.Body.String() != "gzipped app" || recorder.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Test failed: Expected the gzipped script and received %s %v.", recorder.Body.String(),
+			recorder.Header())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/javascript") {
+		t.Errorf("Test failed: Expected text/javascript and received %s.", contentType)
+	}
+	gzipTag := recorder.Header().Get("ETag")
+
+	for _, acceptEncoding := range []string{"", "gzip;q=0", "identity"} {
+		recorder = servePath(router, httpGet, "/static/js/app.js", "Accept-Encoding", acceptEncoding)
+		if recorder.Body.String() != "console.log('app')" || recorder.Header().Get("Content-Encoding") != "" {
+			t.Errorf("Test failed: Expected the plain script for %q and received %s.", acceptEncoding,
+				recorder.Body.String())
+		}
+		if recorder.Header().Get("Vary") != "Accept-Encoding" || recorder.Header().Get("ETag") == gzipTag {
+			t.Errorf("Test failed: Expected a distinct tag varying on Accept-Encoding and received %v.",
+				recorder.Header())
+		}
+	}
+}
+
+// TestServeFSTraversal checks that paths leaving the file system are rejected, and that missing files are answered
+// through the router's Error fallback.
+func TestServeFSTraversal(t *testing.T) {
+	router := NewRouter()
+	router.Error = ProblemJSON
+	router.ServeFS("/static", newTestFS())
+
+	for _, path := range []string{"/static/../secret", "/static/css/../../secret", "/static/css//site.css"} {
+		if recorder := servePath(router, httpGet, path); recorder.Code != http.StatusBadRequest {
+			t.Errorf("Test failed: Expected a 400 for %s and received %d.", path, recorder.Code)
+		}
+	}
+	recorder := servePath(NewFileServer(newTestFS()), httpGet, "/../secret")
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Test failed: Expected a 400 from the file server and received %d.", recorder.Code)
+	}
+
+	recorder = servePath(router, httpGet, "/static/missing.css")
+	if recorder.Code != http.StatusNotFound || recorder.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("Test failed: Expected a problem+json 404 and received %d %v.", recorder.Code, recorder.Header())
+	}
+}
+
+// TestMountFileServer checks that a mounted file server serves the path left after the mount prefix.
+func TestMountFileServer(t *testing.T) {
+	router := NewRouter()
+	router.Mount("/assets", NewFileServer(newTestFS()))
+
+	if body := servePath(router, httpGet, "/assets/css/site.css").Body.String(); body != "body { color: black; }" {
+		t.Errorf("Test failed: Expected the stylesheet and received %s.", body)
+	}
+}
+
+// TestFileETagWithoutModTime checks that files without a modification time, as in an embed.FS, are tagged by their
+// content, so a new version of the same size is not answered with a 304 for the previous version's tag, and that the
+// hash is only computed once for each name and size.
+func TestFileETagWithoutModTime(t *testing.T) {
+	oldFiles := fstest.MapFS{"app.js": {Data: []byte("aaaa")}}
+	oldBuild := NewFileServer(oldFiles)
+	newBuild := NewFileServer(fstest.MapFS{"app.js": {Data: []byte("bbbb")}})
+
+	oldTag := servePath(oldBuild, httpGet, "/app.js").Header().Get("ETag")
+	if oldTag == "" || oldTag == servePath(newBuild, httpGet, "/app.js").Header().Get("ETag") {
+		t.Fatalf("Test failed: Expected different tags for different content and received %s.", oldTag)
+	}
+	recorder := servePath(oldBuild, httpGet, "/app.js", "If-None-Match", oldTag)
+	if recorder.Code != http.StatusNotModified {
+		t.Errorf("Test failed: Expected a 304 for the same version and received %d.", recorder.Code)
+	}
+	recorder = servePath(newBuild, httpGet, "/app.js", "If-None-Match", oldTag)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "bbbb" {
+		t.Errorf("Test failed: Expected 200 bbbb for the new version and received %d %s.", recorder.Code,
+			recorder.Body)
+	}
+
+	// content without a modification time is taken to never change, so the cached hash is kept for the same size
+	oldFiles["app.js"].Data = []byte("cccc")
+	if tag := servePath(oldBuild, httpGet, "/app.js").Header().Get("ETag"); tag != oldTag {
+		t.Errorf("Test failed: Expected %s and received %s.", oldTag, tag)
+	}
+	oldFiles["app.js"].Data = []byte("ccccc")
+	if tag := servePath(oldBuild, httpGet, "/app.js").Header().Get("ETag"); tag == oldTag {
+		t.Errorf("Test failed: Expected a new tag for a new size and received %s.", tag)
+	}
+}
@@ -0,0 +1,111 @@
+/*****************************************************************************
+ * group.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group adds routes to a router under a shared pattern prefix, wrapping their handlers in shared middlewares. Its
+// fallbacks answer requests under the prefix that have no route or failed, in place of the router's.
+type Group struct {
+	Fallbacks
+	router      *HTTPRouter
+	host        *hostTable // host the group's routes are scoped to, or nil
+	prefix      string
+	middlewares []Middleware
+}
+
+// Group creates a group whose routes are prefixed with prefix and wrapped in middlewares. Leading and trailing '/' of
+// the prefix are ignored, as they are for AddRoute patterns, so Group("/api/v1/") and Group("api/v1") are the same.
+func (router *HTTPRouter) Group(prefix string, middlewares ...Middleware) *Group {
+	group := &Group{
+		router:      router,
+		prefix:      trimPattern(prefix),
+		middlewares: middlewares,
+	}
+	router.addGroup(group)
+	return group
+}
+
+// Group creates a nested group whose prefix follows this group's prefix, and whose middlewares run after this group's
+func (group *Group) Group(prefix string, middlewares ...Middleware) *Group {
+	nestedMiddlewares := make([]Middleware, 0, len(group.middlewares)+len(middlewares))
+	nestedMiddlewares = append(nestedMiddlewares, group.middlewares...)
+	nestedMiddlewares = append(nestedMiddlewares, middlewares...)
+
+	nested := &Group{
+		router:      group.router,
+		host:        group.host,
+		prefix:      joinPattern(group.prefix, trimPattern(prefix)),
+		middlewares: nestedMiddlewares,
+	}
+	group.router.addGroup(nested)
+	return nested
+}
+
+// AddRoute adds a route to the group's router with the group prefix prepended to pattern. The group middlewares run
+// after the router middlewares and before any middlewares given in options.
+func (group *Group) AddRoute(method string, pattern string, handler http.HandlerFunc, options ...RouteOption) {
+	group.router.AddRoute(method, group.fullPattern(pattern), handler, group.routeOptions(options)...)
+}
+
+// TryAddRoute adds a route like AddRoute, but returns a ConflictError instead of replacing an existing route
+func (group *Group) TryAddRoute(method string, pattern string, handler http.HandlerFunc, options ...RouteOption) error {
+	return group.router.TryAddRoute(method, group.fullPattern(pattern), handler, group.routeOptions(options)...)
+}
+
+// MustAddRoute adds a route like TryAddRoute, but panics if it conflicts with an existing route
+func (group *Group) MustAddRoute(method string, pattern string, handler http.HandlerFunc, options ...RouteOption) {
+	group.router.MustAddRoute(method, group.fullPattern(pattern), handler, group.routeOptions(options)...)
+}
+
+// routeOptions returns options preceded by the options adding the group middlewares and scoping routes to its host
+func (group *Group) routeOptions(options []RouteOption) []RouteOption {
+	groupOptions := make([]RouteOption, 0, len(options)+2)
+	groupOptions = append(groupOptions, WithMiddleware(group.middlewares...), WithHost(group.hostPattern()))
+	return append(groupOptions, options...)
+}
+
+// hostPattern returns the host pattern the group's routes are scoped to, or "" if they are not scoped to a host
+func (group *Group) hostPattern() string {
+	if group.host == nil {
+		return ""
+	}
+	return group.host.pattern
+}
+
+// fullPattern returns pattern as it is added to the router by the group, with the group prefix prepended
+func (group *Group) fullPattern(pattern string) string {
+	full := joinPattern(group.prefix, trimPattern(pattern))
+	// keep the trailing '/' of the pattern for the router's PathPolicy
+	if pattern != "/" && strings.HasSuffix(pattern, "/") {
+		full += "/"
+	}
+	return full
+}
+
+// addGroup records group so that its fallbacks are used for requests under its prefix
+func (router *HTTPRouter) addGroup(group *Group) {
+	router.updateTable(func(table *routeTable) error {
+		table.groups = append(table.groups, group)
+		table.hostGroups = table.hostGroups || group.host != nil
+		return nil
+	})
+}
+
+// joinPattern joins two trimmed patterns, either of which may be empty
+func joinPattern(prefix string, pattern string) string {
+	if prefix == "" {
+		return pattern
+	}
+	if pattern == "" {
+		return prefix
+	}
+	return prefix + "/" + pattern
+}
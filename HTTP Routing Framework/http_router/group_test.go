@@ -0,0 +1,51 @@
+/******************************************************************************
+ *  group_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for adding routes through route groups.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestGroupPrefix checks that group routes are served under the group prefix, with slashes trimmed like AddRoute.
+func TestGroupPrefix(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api/v1/")
+	api.AddRoute(httpGet, "/users/:user", echoParams)
+	api.AddRoute(httpGet, "/", echoPathHandler)
+
+	if body := serveBody(router, httpGet, "/api/v1/users/cesar"); body != "user=cesar" {
+		t.Errorf("Test failed: Expected %s and received %s.", "user=cesar", body)
+	}
+	if body := serveBody(router, httpGet, "/api/v1"); body != "/api/v1" {
+		t.Errorf("Test failed: Expected %s and received %s.", "/api/v1", body)
+	}
+	if body := serveBody(router, httpGet, "/users/cesar"); body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %s.", body)
+	}
+}
+
+// TestGroupNested checks that nested groups join their prefixes and run their middlewares from the outermost group in.
+func TestGroupNested(t *testing.T) {
+	router := NewRouter()
+	router.Use(tagMiddleware("a"))
+	api := router.Group("api", tagMiddleware("b"))
+	v1 := api.Group("/v1/", tagMiddleware("c"))
+	v1.AddRoute(httpGet, "posts/:post", echoParams, WithMiddleware(tagMiddleware("d")))
+	api.AddRoute(httpGet, "status", echoMethodHandler)
+
+	if body := serveBody(router, httpGet, "/api/v1/posts/7"); body != "abcdpost=7" {
+		t.Errorf("Test failed: Expected %s and received %s.", "abcdpost=7", body)
+	}
+	if body := serveBody(router, httpGet, "/api/status"); body != "abGET" {
+		t.Errorf("Test failed: Expected %s and received %s.", "abGET", body)
+	}
+}
@@ -0,0 +1,57 @@
+/*****************************************************************************
+ * handler.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"log"
+	"net/http"
+)
+
+// ErrorFunc is a handler that returns the error it failed with instead of answering it, so that every failure is
+// answered the same way. It is added with AddErrorRoute, and the router maps the error to a status with MapError and
+// answers it through the Error fallback, unless the handler already started the response.
+type ErrorFunc func(response http.ResponseWriter, request *http.Request) error
+
+// ErrorMapper returns the status a request whose handler failed with err is answered with
+type ErrorMapper func(err error) int
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// AddErrorRoute adds a route like AddRoute whose handler returns the error it failed with for the router to answer
+func (router *HTTPRouter) AddErrorRoute(method string, pattern string, handler ErrorFunc, options ...RouteOption) {
+	router.AddRoute(method, pattern, router.handleErrors(trimPattern(pattern), handler), options...)
+}
+
+// AddErrorRoute adds a route like AddRoute whose handler returns the error it failed with for the router to answer
+func (group *Group) AddErrorRoute(method string, pattern string, handler ErrorFunc, options ...RouteOption) {
+	group.router.AddErrorRoute(method, group.fullPattern(pattern), handler, group.routeOptions(options)...)
+}
+
+// handleErrors returns an http.HandlerFunc answering the errors handler fails with. Errors mapped to a 5xx status
+// are logged with the request method and the route's trimmed pattern, as they are unexpected failures whose message
+// isn't shown to the client. Errors returned after the response was started are only logged.
+func (router *HTTPRouter) handleErrors(pattern string, handler ErrorFunc) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		tracked, wrapped := trackResponse(response)
+		err := handler(wrapped, request)
+		if err == nil {
+			return
+		}
+
+		mapError := router.MapError
+		if mapError == nil {
+			mapError = ErrorStatus
+		}
+		status := mapError(err)
+		if status >= http.StatusInternalServerError || tracked.wroteHeader {
+			log.Printf("http_router: error serving %s /%s: %v", request.Method, pattern, err)
+		}
+		if !tracked.wroteHeader {
+			router.WriteError(response, request, status, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/******************************************************************************
+ *  handler_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for error-returning handlers and the router's error mapping.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// errMaintenance is an error type mapped to a 503 by newErrorRouter
+type errMaintenance struct{}
+
+func (errMaintenance) Error() string { return "down for maintenance" }
+
+// findUser is an error-returning handler failing for every user but bob, with an error depending on the user
+func findUser(response http.ResponseWriter, request *http.Request) error {
+	switch user := Param(request, "user"); user {
+	case "bob":
+		response.Write([]byte("found bob"))
+		return nil
+	case "root":
+		return fmt.Errorf("user %s: %w", user, ErrUnauthorized)
+	case "taken":
+		return ErrConflict
+	case "sleepy":
+		return errMaintenance{}
+	case "partial":
+		response.Write([]byte("partial"))
+		return errors.New("connection reset")
+	default:
+		return errors.New("query failed for " + user)
+	}
+}
+
+// newErrorRouter creates a router holding findUser under /users and a group answering with ProblemJSON under /api
+func newErrorRouter() *HTTPRouter {
+	router := NewRouter()
+	router.MapError = func(err error) int {
+		if errors.As(err, &errMaintenance{}) {
+			return http.StatusServiceUnavailable
+		}
+		return ErrorStatus(err)
+	}
+	router.AddErrorRoute(httpGet, "/users/:user", findUser)
+	router.AddErrorRoute(httpGet, "/missing/:user", func(response http.ResponseWriter, request *http.Request) error {
+		return fmt.Errorf("no user %s: %w", Param(request, "user"), ErrNotFound)
+	})
+
+	api := router.Group("/api")
+	api.Error = ProblemJSON
+	api.AddErrorRoute(httpGet, "/users/:user", findUser)
+	return router
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestErrorFunc checks that the errors of error-returning handlers are mapped to statuses by MapError and answered
+// through the Error fallback of the route's group.
+func TestErrorFunc(t *testing.T) {
+	router := newErrorRouter()
+	logged := captureLog(t)
+
+	tests := []struct {
+		path     string
+		status   int
+		expected string
+	}{
+		{"/users/bob", http.StatusOK, "found bob"},
+		{"/missing/alice", http.StatusNotFound, "404 no user alice: not found\n"},
+		{"/users/root", http.StatusUnauthorized, "401 user root: unauthorized\n"},
+		{"/users/taken", http.StatusConflict, "409 conflict\n"},
+		{"/users/sleepy", http.StatusServiceUnavailable, "503 service unavailable\n"},
+		{"/users/alice", http.StatusInternalServerError, "500 internal server error\n"},
+		{"/api/users/root", http.StatusUnauthorized, `"detail":"user root: unauthorized"`},
+	}
+	for _, test := range tests {
+		recorder := servePath(router, httpGet, test.path)
+		if body := recorder.Body.String(); recorder.Code != test.status || !strings.Contains(body, test.expected) {
+			t.Errorf("Test failed: Expected %d %q for %s and received %d %q.", test.status, test.expected,
+				test.path, recorder.Code, body)
+		}
+	}
+
+	// only the server errors are logged, with the route they were served by
+	for _, expected := range []string{
+		"http_router: error serving GET /users/:user: down for maintenance\n",
+		"http_router: error serving GET /users/:user: query failed for alice\n",
+	} {
+		if !strings.Contains(logged.String(), expected) {
+			t.Errorf("Test failed: Expected %q to be logged and received %q.", expected, logged)
+		}
+	}
+	if count := strings.Count(logged.String(), "http_router:"); count != 2 {
+		t.Errorf("Test failed: Expected 2 errors to be logged and received %d.", count)
+	}
+}
+
+// TestErrorFuncStarted checks that an error returned after the response was started is logged without answering it
+// a second time.
+func TestErrorFuncStarted(t *testing.T) {
+	router := newErrorRouter()
+	logged := captureLog(t)
+
+	recorder := servePath(router, httpGet, "/users/partial")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "partial" {
+		t.Errorf("Test failed: Expected 200 partial and received %d %s.", recorder.Code, recorder.Body)
+	}
+	if expected := "error serving GET /users/:user: connection reset"; !strings.Contains(logged.String(), expected) {
+		t.Errorf("Test failed: Expected %s to be logged and received %s.", expected, logged)
+	}
+}
@@ -0,0 +1,190 @@
+/*****************************************************************************
+ * host.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// WithHost scopes the route being added to requests whose host matches pattern, such as `api.example.com` or
+// `:tenant.example.com`. Host patterns are made of labels separated by '.', each either static, and matched regardless
+// of case, or a capture of the form `:variable_name`, optionally constrained as path captures are. Host captures are
+// given by Params along with the path captures, before them. The port of the request host is ignored. HTTPRouter.URL
+// only builds the path of routes scoped to a host.
+//
+// Routes of a host pattern matching the request are tried before the routes without a host, and a static label takes
+// precedence over a capture, as it does in paths. A request whose host matches no pattern, or whose path has no route
+// under the hosts it matches, falls back to the routes without a host.
+func WithHost(pattern string) RouteOption {
+	return func(route *RoutesField) {
+		route.Host = pattern
+	}
+}
+
+// Host creates a group whose routes are scoped to requests whose host matches pattern, as WithHost scopes them, and
+// wrapped in middlewares. Its fallbacks answer requests for matching hosts that have no route or failed.
+func (router *HTTPRouter) Host(pattern string, middlewares ...Middleware) *Group {
+	group := &Group{
+		router:      router,
+		host:        newHostTable(normalizeHost(pattern)),
+		middlewares: middlewares,
+	}
+	router.addGroup(group)
+	return group
+}
+
+// hostTable holds the routing tries of the routes scoped to a host pattern. Groups scoped to a host use one without
+// tries to match request hosts.
+type hostTable struct {
+	pattern  string
+	labels   []string
+	matchers []func(string) bool // check of each label, comparing static labels and checking capture constraints
+	trees    map[string]*node    // routing trie of each method
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// splitHost splits a host pattern or request host into its labels. Dots inside a capture's regular expression
+// constraint don't separate labels.
+func splitHost(host string) []string {
+	var labels []string
+	depth, start := 0, 0
+	for i := 0; i < len(host); i++ {
+		switch host[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '.':
+			if depth == 0 {
+				labels = append(labels, host[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(labels, host[start:])
+}
+
+// normalizeHost returns a host pattern with its static labels in lower case and without a trailing '.', or "" for
+// the empty pattern. Patterns with empty labels panic.
+func normalizeHost(pattern string) string {
+	pattern = strings.TrimSuffix(pattern, ".")
+	if pattern == "" {
+		return ""
+	}
+	labels := splitHost(pattern)
+	for i, label := range labels {
+		if label == "" {
+			panic("http_router: host pattern " + pattern + " has an empty label")
+		}
+		if !isCapture(label) {
+			labels[i] = strings.ToLower(label)
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// newHostTable creates an empty host table for a normalized host pattern
+func newHostTable(pattern string) *hostTable {
+	host := &hostTable{pattern: pattern, labels: splitHost(pattern), trees: map[string]*node{}}
+	for _, label := range host.labels {
+		label := label
+		if isCapture(label) {
+			host.matchers = append(host.matchers, compileConstraint(captureConstraint(label)))
+		} else {
+			host.matchers = append(host.matchers, func(requestLabel string) bool { return requestLabel == label })
+		}
+	}
+	return host
+}
+
+// requestHostLabels returns the labels of the request host in lower case and without its port, or nil if the table
+// has nothing scoped to a host
+func (table *routeTable) requestHostLabels(request *http.Request) []string {
+	if len(table.hosts) == 0 && !table.hostGroups {
+		return nil
+	}
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		host = name
+	}
+	return splitHost(strings.ToLower(strings.TrimSuffix(host, ".")))
+}
+
+// matches reports whether the request host labels match the host pattern
+func (host *hostTable) matches(hostLabels []string) bool {
+	if len(host.labels) != len(hostLabels) {
+		return false
+	}
+	for i, matcher := range host.matchers {
+		if matcher != nil && !matcher(hostLabels[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// hostPrecedes reports whether the first host pattern takes precedence over the second. Patterns of different lengths
+// never match the same host, otherwise the first label that differs in kind decides, as captureRank ranks them.
+func hostPrecedes(first []string, second []string) bool {
+	for i := 0; i < len(first) && i < len(second); i++ {
+		if firstRank, secondRank := captureRank(first[i]), captureRank(second[i]); firstRank != secondRank {
+			return firstRank < secondRank
+		}
+	}
+	return false
+}
+
+// addHostCaptures adds the values captured from the request host labels by the host pattern of route to captures
+func addHostCaptures(captures url.Values, route *RoutesField, hostLabels []string) {
+	for i, label := range route.hostLabels {
+		if isCapture(label) {
+			captures.Add(captureName(label), hostLabels[i])
+		}
+	}
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// treesFor returns the routing tries of the routes scoped to host, or of the routes without a host if host is empty,
+// ready to be changed in this table. The tries of a new host are created empty.
+func (table *routeTable) treesFor(host string) map[string]*node {
+	if host == "" {
+		return table.trees
+	}
+	for i, existing := range table.hosts {
+		if existing.pattern == host {
+			copied := *existing
+			copied.trees = maps.Clone(existing.trees)
+			table.hosts[i] = &copied
+			return copied.trees
+		}
+	}
+
+	added := newHostTable(host)
+	table.hosts = append(table.hosts, added)
+	sort.SliceStable(table.hosts, func(i, j int) bool {
+		return hostPrecedes(table.hosts[i].labels, table.hosts[j].labels)
+	})
+	return added.trees
+}
+
+// pruneHosts drops the tables of host patterns left without routes
+func (table *routeTable) pruneHosts() {
+	table.hosts = slices.DeleteFunc(table.hosts, func(host *hostTable) bool {
+		return len(host.trees) == 0
+	})
+}
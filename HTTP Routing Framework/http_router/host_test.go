@@ -0,0 +1,158 @@
+/******************************************************************************
+ *  host_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for routes scoped to host patterns.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// writeHandler returns a handler writing body to the response
+func writeHandler(body string) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		response.Write([]byte(body))
+	}
+}
+
+// newHostRouter creates a router with routes for a static host, a captured tenant host, and no host
+func newHostRouter() *HTTPRouter {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user", echoParams, WithHost(":tenant.example.com"))
+	api := router.Host("API.example.com.")
+	api.AddRoute(httpGet, "/users/:user", writeHandler("api"))
+	api.AddRoute(httpPost, "/users/:user", writeHandler("api post"))
+	router.AddRoute(httpGet, "/users/:user", writeHandler("any host"))
+	router.AddRoute(httpGet, "/healthz", writeHandler("ok"))
+	return router
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestHostRouting checks that a static host takes precedence over a captured one, that host captures come before the
+// path captures, and that other hosts and unrouted paths fall back to the routes without a host.
+func TestHostRouting(t *testing.T) {
+	router := newHostRouter()
+	tests := []struct {
+		host     string
+		path     string
+		expected string
+	}{
+		{"api.example.com:8080", "/users/bob", "api"},
+		{"Api.Example.com", "/users/bob", "api"},
+		{"acme.example.com", "/users/bob", "tenant=acme&user=bob"},
+		{"example.com", "/users/bob", "any host"},
+		{"a.b.example.com", "/users/bob", "any host"},
+		{"acme.example.com", "/healthz", "ok"},
+		{"api.example.com", "/healthz", "ok"},
+	}
+	for _, test := range tests {
+		if body := servePath(router, httpGet, test.path, "Host", test.host).Body.String(); body != test.expected {
+			t.Errorf("Test failed: Expected %s for %s%s and received %s.", test.expected, test.host, test.path, body)
+		}
+	}
+}
+
+// TestHostMethods checks that the methods allowed for a path include those of the routes for matching hosts only.
+func TestHostMethods(t *testing.T) {
+	router := newHostRouter()
+
+	recorder := servePath(router, "DELETE", "/users/bob", "Host", "api.example.com")
+	allow := recorder.Header().Get("Allow")
+	if recorder.Code != http.StatusMethodNotAllowed || allow != "GET, HEAD, OPTIONS, POST" {
+		t.Errorf("Test failed: Expected a 405 allowing POST and received %d %s.", recorder.Code, allow)
+	}
+	recorder = servePath(router, "DELETE", "/users/bob", "Host", "acme.example.com")
+	if allow := recorder.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Test failed: Expected GET, HEAD, OPTIONS and received %s.", allow)
+	}
+	if body := servePath(router, "HEAD", "/users/bob", "Host", "api.example.com").Body.String(); body != "" {
+		t.Errorf("Test failed: Expected an empty HEAD body and received %s.", body)
+	}
+}
+
+// TestHostConstraint checks that constraints on host captures take part in matching.
+func TestHostConstraint(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/", echoParams, WithHost(":shard<int>.example.com"))
+	router.AddRoute(httpGet, "/", echoParams, WithHost(":version{v[0-9]+}.example.com"))
+	router.AddRoute(httpGet, "/", writeHandler("any host"))
+
+	tests := map[string]string{
+		"12.example.com":  "shard=12",
+		"v12.example.com": "version=v12",
+		"www.example.com": "any host",
+	}
+	for host, expected := range tests {
+		if body := servePath(router, httpGet, "/", "Host", host).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, host, body)
+		}
+	}
+}
+
+// TestHostGroupFallbacks checks that the fallbacks of a host group answer requests for its host only.
+func TestHostGroupFallbacks(t *testing.T) {
+	router := newHostRouter()
+	api := router.Host("api.example.com")
+	api.NotFound = writeHandler("api not found")
+
+	body := servePath(router, httpGet, "/missing", "Host", "api.example.com").Body.String()
+	if body != "api not found" {
+		t.Errorf("Test failed: Expected the host group's 404 and received %s.", body)
+	}
+	body = servePath(router, httpGet, "/missing", "Host", "example.com").Body.String()
+	if body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected the router's 404 and received %s.", body)
+	}
+}
+
+// TestHostRegistration checks that routes of the same shape only conflict under the same host, and that host routes
+// are walked with their host and removed through their group.
+func TestHostRegistration(t *testing.T) {
+	router := newHostRouter()
+
+	err := router.TryAddRoute(httpGet, "/users/:id", echoParams, WithHost("api.example.com"))
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Host != "api.example.com" {
+		t.Fatalf("Test failed: Expected a conflict under api.example.com and received %v.", err)
+	}
+	expected := "http_router: route GET api.example.com/users/:id conflicts with existing route GET "
+	if err.Error() != expected+"api.example.com/users/:user" {
+		t.Errorf("Test failed: Received unexpected message %s.", err)
+	}
+	if err := router.TryAddRoute(httpGet, "/users/:id", echoParams, WithHost("www.example.com")); err != nil {
+		t.Errorf("Test failed: Expected no conflict under another host and received %v.", err)
+	}
+
+	var patterns []string
+	router.Walk(func(method string, pattern string, name string, middlewares int) error {
+		patterns = append(patterns, method+" "+pattern)
+		return nil
+	})
+	if len(patterns) != 6 || patterns[0] != "GET /healthz" || patterns[3] != "POST api.example.com/users/:user" ||
+		patterns[5] != "GET :tenant.example.com/users/:user" {
+		t.Errorf("Test failed: Received unexpected walk order %v.", patterns)
+	}
+
+	if !router.Host("api.example.com").RemoveRoute(httpGet, "/users/:name") {
+		t.Fatalf("Test failed: Expected the host route to be removed.")
+	}
+	if router.RemoveRoute(httpGet, "/users/:id") && router.RemoveRoute(httpGet, "/users/:id") {
+		t.Errorf("Test failed: Expected the route without a host to be removed once.")
+	}
+	body := servePath(router, httpGet, "/users/bob", "Host", "api.example.com").Body.String()
+	if body != "tenant=api&user=bob" {
+		t.Errorf("Test failed: Expected the captured host to serve api.example.com and received %s.", body)
+	}
+}
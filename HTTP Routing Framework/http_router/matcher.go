@@ -0,0 +1,123 @@
+/*****************************************************************************
+ * matcher.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Matcher is a condition on a request beyond its method and path that a route can require, such as a header value.
+// Routes of the same method and shape can be added with different matchers. A request is served by the route of the
+// highest precedence pattern, as IsHigherPrecedence orders them, and among the routes of that pattern whose matchers
+// it meets by the one with the most matchers. If it meets the matchers of none of them, the routes of lower
+// precedence patterns are tried.
+type Matcher struct {
+	// Name describes the condition, e.g. `header Content-Type=application/json`. Routes of the same method and shape
+	// are the same route, replacing each other, when their matchers have the same names.
+	Name string
+	// Match reports whether request meets the condition
+	Match func(request *http.Request) bool
+}
+
+// WithMatchers makes the route being added require that requests meet every one of matchers
+func WithMatchers(matchers ...Matcher) RouteOption {
+	return func(route *RoutesField) {
+		route.Matchers = append(route.Matchers, matchers...)
+	}
+}
+
+// MatchHeader matches requests with a header called name holding value, or holding any value if value is empty
+func MatchHeader(name string, value string) Matcher {
+	return Matcher{
+		Name: fmt.Sprintf("header %s=%s", http.CanonicalHeaderKey(name), value),
+		Match: func(request *http.Request) bool {
+			values := request.Header.Values(name)
+			if value == "" {
+				return len(values) > 0
+			}
+			return slices.Contains(values, value)
+		},
+	}
+}
+
+// MatchQuery matches requests with a query parameter called name holding value, or holding any value if value is
+// empty
+func MatchQuery(name string, value string) Matcher {
+	return Matcher{
+		Name: fmt.Sprintf("query %s=%s", name, value),
+		Match: func(request *http.Request) bool {
+			values, ok := request.URL.Query()[name]
+			if value == "" {
+				return ok
+			}
+			return slices.Contains(values, value)
+		},
+	}
+}
+
+// MatchContentType matches requests whose Content-Type header has the given media type, regardless of case and of
+// parameters such as the charset, e.g. MatchContentType("application/json") matches `application/json; charset=utf-8`
+func MatchContentType(mediaType string) Matcher {
+	return Matcher{
+		Name: "content-type " + strings.ToLower(mediaType),
+		Match: func(request *http.Request) bool {
+			requestType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+			return err == nil && strings.EqualFold(requestType, mediaType)
+		},
+	}
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// matches reports whether request meets every matcher of route. A nil request only meets routes without matchers.
+func (route *RoutesField) matches(request *http.Request) bool {
+	for _, matcher := range route.Matchers {
+		if request == nil || !matcher.Match(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// matcherNames returns the sorted names of the matchers of route
+func (route *RoutesField) matcherNames() []string {
+	names := make([]string, len(route.Matchers))
+	for i, matcher := range route.Matchers {
+		names[i] = matcher.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+// sameMatchers finds the route among routes with the same matchers as route, or returns nil
+func sameMatchers(routes []*RoutesField, route *RoutesField) *RoutesField {
+	names := route.matcherNames()
+	for _, existing := range routes {
+		if slices.Equal(existing.matcherNames(), names) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// withRoute returns a copy of routes where route replaces the route with the same matchers, or is added after the
+// routes with at least as many matchers
+func withRoute(routes []*RoutesField, route *RoutesField) []*RoutesField {
+	if existing := sameMatchers(routes, route); existing != nil {
+		i := slices.Index(routes, existing)
+		return slices.Replace(slices.Clone(routes), i, i+1, route)
+	}
+	i := 0
+	for i < len(routes) && len(routes[i].Matchers) >= len(route.Matchers) {
+		i++
+	}
+	return slices.Insert(slices.Clone(routes), i, route)
+}
@@ -0,0 +1,111 @@
+/******************************************************************************
+ *  matcher_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for routes requiring requests to meet matchers beyond their method
+ *    and path.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestMatchContentType checks that requests to the same path are served by the route whose matchers they meet, the
+// route with the most matchers first, and by the route without matchers otherwise.
+func TestMatchContentType(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpPost, "/users", writeHandler("form"),
+		WithMatchers(MatchContentType("application/x-www-form-urlencoded")))
+	router.AddRoute(httpPost, "/users", writeHandler("json"), WithMatchers(MatchContentType("application/json")))
+	router.AddRoute(httpPost, "/users", writeHandler("json v2"),
+		WithMatchers(MatchContentType("application/json"), MatchHeader("X-Version", "2")))
+	router.AddRoute(httpPost, "/users", writeHandler("other"))
+
+	tests := []struct {
+		contentType string
+		version     string
+		expected    string
+	}{
+		{"application/json", "", "json"},
+		{"Application/JSON; charset=utf-8", "1", "json"},
+		{"application/json", "2", "json v2"},
+		{"application/x-www-form-urlencoded", "2", "form"},
+		{"text/plain", "2", "other"},
+		{"", "", "other"},
+	}
+	for _, test := range tests {
+		recorder := servePath(router, httpPost, "/users", "Content-Type", test.contentType, "X-Version", test.version)
+		if body := recorder.Body.String(); body != test.expected {
+			t.Errorf("Test failed: Expected %s for %s version %s and received %s.", test.expected, test.contentType,
+				test.version, body)
+		}
+	}
+}
+
+// TestMatcherFallThrough checks that a request meeting none of the matchers of the highest precedence pattern is
+// served by a lower precedence pattern, or not found.
+func TestMatcherFallThrough(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/files/latest", writeHandler("beta"), WithMatchers(MatchQuery("channel", "beta")))
+	router.AddRoute(httpGet, "/files/:file", echoParams)
+	router.AddRoute(httpGet, "/reports", writeHandler("report"), WithMatchers(MatchQuery("format", "")))
+
+	tests := map[string]string{
+		"/files/latest?channel=beta":   "beta",
+		"/files/latest?channel=stable": "file=latest",
+		"/reports?format=":             "report",
+		"/reports?page=2":              "404 page not found\n",
+	}
+	for path, expected := range tests {
+		if body := servePath(router, httpGet, path).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %q for %s and received %q.", expected, path, body)
+		}
+	}
+}
+
+// TestMatcherRegistration checks that routes of the same shape only replace each other or conflict when their
+// matchers have the same names, that their names are kept apart, and that RemoveRoute removes all of them.
+func TestMatcherRegistration(t *testing.T) {
+	router := NewRouter()
+	json := WithMatchers(MatchContentType("application/json"), MatchHeader("x-version", ""))
+	router.AddRoute(httpPost, "/users", writeHandler("json"), json, WithName("json"))
+	router.AddRoute(httpPost, "/users", writeHandler("other"), WithName("other"))
+	router.AddRoute(httpPost, "/users", writeHandler("json replaced"),
+		WithMatchers(MatchHeader("X-Version", ""), MatchContentType("APPLICATION/JSON")))
+
+	if len(router.Routes()) != 2 {
+		t.Fatalf("Test failed: Expected 2 routes and found %d.", len(router.Routes()))
+	}
+	recorder := servePath(router, httpPost, "/users", "Content-Type", "application/json", "X-Version", "1")
+	if body := recorder.Body.String(); body != "json replaced" {
+		t.Errorf("Test failed: Expected json replaced and received %s.", body)
+	}
+	if _, err := router.URL("json"); err == nil {
+		t.Errorf("Test failed: Expected the replaced route's name to be released.")
+	}
+	if path, err := router.URL("other"); err != nil || path != "/users" {
+		t.Errorf("Test failed: Expected /users and received %s (%v).", path, err)
+	}
+
+	var conflict *ConflictError
+	if err := router.TryAddRoute(httpPost, "/users", echoParams); !errors.As(err, &conflict) {
+		t.Errorf("Test failed: Expected a conflict with the route without matchers and received %v.", err)
+	}
+
+	info := router.RouteTable()
+	if len(info) != 2 || strings.Join(info[0].Matchers, ", ") != "header X-Version=, content-type application/json" {
+		t.Errorf("Test failed: Received unexpected route table %+v.", info)
+	}
+
+	if !router.RemoveRoute(httpPost, "/users") || len(router.Routes()) != 0 {
+		t.Errorf("Test failed: Expected every route of the shape to be removed.")
+	}
+}
@@ -0,0 +1,92 @@
+/*****************************************************************************
+ * methods.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// allowedMethods returns the sorted methods the given request, its host labels and its path directories are routed
+// for, including the HEAD and OPTIONS methods the router answers automatically, or nil if no route of any method
+// matches
+func (router *HTTPRouter) allowedMethods(table *routeTable, request *http.Request, hostLabels []string,
+	requestSegments []string) []string {
+	var allowed []string
+	for _, method := range table.methods(hostLabels) {
+		if method != anyMethod && router.matchPath(table, request, hostLabels, method, requestSegments) != nil {
+			allowed = append(allowed, method)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if router.HandleHEAD && containsMethod(allowed, http.MethodGet) && !containsMethod(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	if router.HandleOPTIONS && !containsMethod(allowed, http.MethodOptions) {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// methods returns the methods routes were added for without a host or for a host pattern matching the request host
+func (table *routeTable) methods(hostLabels []string) []string {
+	methods := make([]string, 0, len(table.trees))
+	for method := range table.trees {
+		methods = append(methods, method)
+	}
+	for _, host := range table.hosts {
+		if !host.matches(hostLabels) {
+			continue
+		}
+		for method := range host.trees {
+			if !containsMethod(methods, method) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+// containsMethod reports whether methods contains method
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// optionsHandler answers an OPTIONS request with a 204 whose Allow header lists the methods the path is routed for
+func optionsHandler(allowed []string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Allow", strings.Join(allowed, ", "))
+		response.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// headHandler serves a HEAD request through a GET handler, keeping its headers and status but discarding its body
+func headHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		handler.ServeHTTP(headResponseWriter{response}, request)
+	})
+}
+
+// headResponseWriter is a response writer that discards everything written to the body
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards body, reporting it as written so handlers carry on as they would for a GET request
+func (response headResponseWriter) Write(body []byte) (int, error) {
+	return len(body), nil
+}
@@ -0,0 +1,74 @@
+/******************************************************************************
+ *  methods_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for the automatic HEAD and OPTIONS handling of the router.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestAutomaticHead checks that a HEAD request is served by the GET route without a body, unless a HEAD route exists.
+func TestAutomaticHead(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user", func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("X-User", Param(request, "user"))
+		response.Write([]byte("profile"))
+	})
+	router.AddRoute(http.MethodHead, "/posts", echoMethodHandler)
+
+	request := httptest.NewRequest(http.MethodHead, "http://localhost:8080/users/cesar", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	if response.StatusCode != httpOK {
+		t.Errorf("Test failed: Router gave non-200 status code: %d", response.StatusCode)
+	}
+	if response.Header.Get("X-User") != "cesar" {
+		t.Errorf("Test failed: Expected the GET handler's headers and received %v.", response.Header)
+	}
+	if body := responseBodyToString(response); body != "" {
+		t.Errorf("Test failed: Expected an empty body and received %s.", body)
+	}
+
+	if body := serveBody(router, http.MethodHead, "/posts"); body != http.MethodHead {
+		t.Errorf("Test failed: Expected %s and received %s.", http.MethodHead, body)
+	}
+}
+
+// TestAutomaticOptions checks that an OPTIONS request lists the allowed methods, unless an OPTIONS route exists.
+func TestAutomaticOptions(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user", echoMethodHandler)
+	router.AddRoute(httpPut, "/users/:user", echoMethodHandler)
+	router.AddRoute(http.MethodOptions, "/posts", echoMethodHandler)
+
+	request := httptest.NewRequest(http.MethodOptions, "http://localhost:8080/users/cesar", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	if response.StatusCode != http.StatusNoContent {
+		t.Errorf("Test failed: Router gave non-204 status code: %d", response.StatusCode)
+	}
+	if allow := response.Header.Get("Allow"); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("Test failed: Expected Allow header %s and received %s.", "GET, HEAD, OPTIONS, PUT", allow)
+	}
+
+	if body := serveBody(router, http.MethodOptions, "/posts"); body != http.MethodOptions {
+		t.Errorf("Test failed: Expected %s and received %s.", http.MethodOptions, body)
+	}
+	if body := serveBody(router, http.MethodOptions, "/missing"); body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %s.", body)
+	}
+}
@@ -0,0 +1,40 @@
+/*****************************************************************************
+ * middleware.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import "net/http"
+
+// Middleware wraps a handler to run code before and/or after it, e.g. for logging, authentication or recovery
+type Middleware func(http.Handler) http.Handler
+
+// RouteOption configures a single route added with AddRoute
+type RouteOption func(route *RoutesField)
+
+// Use adds middlewares that wrap every request served by the router, including those answered with a 404. Router
+// middlewares run before the middlewares of the matched route, and both run in the order they were added, so the
+// first middleware added is the outermost.
+func (router *HTTPRouter) Use(middlewares ...Middleware) {
+	router.updateTable(func(table *routeTable) error {
+		table.middlewares = append(table.middlewares, middlewares...)
+		return nil
+	})
+}
+
+// WithMiddleware adds middlewares that only wrap the handler of the route being added, after the router middlewares
+func WithMiddleware(middlewares ...Middleware) RouteOption {
+	return func(route *RoutesField) {
+		route.Middlewares = append(route.Middlewares, middlewares...)
+	}
+}
+
+// chain wraps handler in middlewares so that the first middleware is the outermost
+func chain(handler http.Handler, middlewares []Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,71 @@
+/******************************************************************************
+ *  middleware_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for router and route middlewares.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// tagMiddleware returns a middleware that writes tag to the response body before calling the next handler
+func tagMiddleware(tag string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Write([]byte(tag))
+			next.ServeHTTP(response, request)
+		})
+	}
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestMiddlewareOrder checks that router middlewares run before route middlewares, each in the order they were added.
+func TestMiddlewareOrder(t *testing.T) {
+	router := NewRouter()
+	router.Use(tagMiddleware("a"), tagMiddleware("b"))
+	router.AddRoute(httpGet, "/users/:user", echoParams, WithMiddleware(tagMiddleware("c"), tagMiddleware("d")))
+	router.AddRoute(httpGet, "/index.html", echoMethodHandler)
+	router.Use(tagMiddleware("e"))
+
+	if body := serveBody(router, httpGet, "/users/cesar"); body != "abecduser=cesar" {
+		t.Errorf("Test failed: Expected %s and received %s.", "abecduser=cesar", body)
+	}
+	if body := serveBody(router, httpGet, "/index.html"); body != "abeGET" {
+		t.Errorf("Test failed: Expected %s and received %s.", "abeGET", body)
+	}
+}
+
+// TestMiddlewareNotFound checks that router middlewares also wrap requests without a matching route.
+func TestMiddlewareNotFound(t *testing.T) {
+	router := NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			response.Header().Set("X-Wrapped", "true")
+			next.ServeHTTP(response, request)
+		})
+	})
+
+	request := httptest.NewRequest(httpGet, "http://localhost:8080/missing", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	if response.StatusCode != httpNotFound {
+		t.Errorf("Test failed: Router gave non-404 status code: %d", response.StatusCode)
+	}
+	if response.Header.Get("X-Wrapped") != "true" {
+		t.Errorf("Test failed: Expected the 404 response to pass through the router middleware.")
+	}
+}
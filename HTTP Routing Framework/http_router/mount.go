@@ -0,0 +1,97 @@
+/*****************************************************************************
+ * mount.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// anyMethod is the method of the routes added by Mount, which serve requests of any method unless a route of their
+// own method takes precedence
+const anyMethod = "*"
+
+// mountSegment is the unnamed catch-all ending the pattern of the routes added by Mount under their prefix
+const mountSegment = "*"
+
+// mountPrefixKey is the request context key the path prefix stripped from a request by Mount is stored under
+type mountPrefixKey struct{}
+
+// Mount hands every request whose path is prefix or starts with its directories to handler, whatever its method,
+// with prefix stripped from its path, e.g. after Mount("/admin", admin) a request for `/admin/users/` reaches admin as
+// `/users/`, and one for `/admin` as `/`. handler can be another HTTPRouter or any http.Handler. Values captured by
+// prefix, such as `/tenants/:tenant`, are passed down through Params, before any values the mounted router captures.
+// A mounted router redirecting requests under its PathPolicy or CasePolicy puts the stripped prefix back on the
+// location, so `/admin/users/` is redirected to `/admin/users` rather than `/users`.
+//
+// Mounts compete with the routes added for the method of a request, and with GET routes for HEAD requests, by path
+// precedence as IsHigherPrecedence orders patterns, so a mount at `/api` takes requests under it from a catch-all at
+// the root, while a route at `/api/status` takes its path from the mount. Of patterns of the same precedence the route
+// wins. options apply to both routes Mount adds: prefix, and prefix followed by a catch-all.
+func (router *HTTPRouter) Mount(prefix string, handler http.Handler, options ...RouteOption) {
+	prefix = trimPattern(prefix)
+	mounted := mountHandler(handler)
+	router.AddRoute(anyMethod, prefix, mounted, options...)
+	router.AddRoute(anyMethod, joinPattern(prefix, mountSegment), mounted, options...)
+}
+
+// Mount hands requests under prefix, with the group prefix prepended, to handler as HTTPRouter.Mount does, wrapped in
+// the group middlewares and scoped to the group's host
+func (group *Group) Mount(prefix string, handler http.Handler, options ...RouteOption) {
+	group.router.Mount(group.fullPattern(prefix), handler, group.routeOptions(options)...)
+}
+
+// mountHandler returns the handler of the routes added by Mount, which serves the request through handler with the
+// mount prefix stripped from its path. The stripped path keeps the trailing '/' of the request path, and the stripped
+// prefix is added to the prefix stripped by any mount the request already went through.
+func mountHandler(handler http.Handler) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		params := Params(request)
+		path := "/"
+		if rest, ok := params[captureName(mountSegment)]; ok {
+			path += rest[0]
+			if strings.HasSuffix(request.URL.Path, "/") {
+				path += "/"
+			}
+		}
+
+		parent := url.Values{}
+		for name, values := range params {
+			if name != captureName(mountSegment) {
+				parent[name] = values
+			}
+		}
+		mounted := withParams(request, parent)
+		prefix := mountPrefix(request) + strippedPrefix(request.URL.Path, path)
+		mounted = mounted.WithContext(context.WithValue(mounted.Context(), mountPrefixKey{}, prefix))
+		mounted.URL = new(url.URL)
+		*mounted.URL = *request.URL
+		mounted.URL.Path = path
+		mounted.URL.RawPath = ""
+		handler.ServeHTTP(response, mounted)
+	}
+}
+
+// strippedPrefix returns the prefix of requestPath that was stripped to leave path, both cleaned since the parent
+// router may have routed an unclean path, or "" if requestPath doesn't end with path
+func strippedPrefix(requestPath string, path string) string {
+	requestPath, rest := cleanPath(requestPath), strings.TrimSuffix(cleanPath(path), "/")
+	if !strings.HasSuffix(requestPath, rest) {
+		return ""
+	}
+	// a mount at the root strips nothing
+	return strings.TrimSuffix(strings.TrimSuffix(requestPath, rest), "/")
+}
+
+// mountPrefix returns the path prefix the mounts a request went through stripped from its path, or "" if it went
+// through none
+func mountPrefix(request *http.Request) string {
+	prefix, _ := request.Context().Value(mountPrefixKey{}).(string)
+	return prefix
+}
@@ -0,0 +1,167 @@
+/******************************************************************************
+ *  mount_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for handing requests under a prefix to mounted handlers and routers.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// echoRequestHandler writes the method, path and raw query of the request to the response body
+func echoRequestHandler(response http.ResponseWriter, request *http.Request) {
+	response.Write([]byte(request.Method + " " + request.URL.Path + "?" + request.URL.RawQuery))
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestMountHandler checks that requests of any method under the prefix reach the mounted handler with the prefix
+// stripped, while routes of the request method take precedence.
+func TestMountHandler(t *testing.T) {
+	router := NewRouter()
+	router.Mount("/admin/", http.HandlerFunc(echoRequestHandler))
+	router.AddRoute(httpGet, "/admin/stats", writeHandler("stats"))
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{httpGet, "/admin", "GET /?"},
+		{httpPost, "/admin/users/bob/", "POST /users/bob/?"},
+		{"PROPFIND", "/admin/files?depth=1", "PROPFIND /files?depth=1"},
+		{httpGet, "/admin/stats", "stats"},
+		{httpPost, "/admin/stats", "POST /stats?"},
+		{httpGet, "/administrator", "404 page not found\n"},
+	}
+	for _, test := range tests {
+		if body := serveBody(router, test.method, test.path); body != test.expected {
+			t.Errorf("Test failed: Expected %q for %s %s and received %q.", test.expected, test.method, test.path,
+				body)
+		}
+	}
+}
+
+// TestMountRouter checks that a mounted router routes the stripped path, sees the values captured by the prefix, and
+// answers HEAD, OPTIONS and unknown paths itself.
+func TestMountRouter(t *testing.T) {
+	child := NewRouter()
+	child.AddRoute(httpGet, "/users/:user", echoParams)
+	child.AddRoute(httpGet, "/", echoParams)
+	child.NotFound = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Write([]byte("child not found"))
+	})
+
+	router := NewRouter()
+	router.Mount("/tenants/:tenant/admin", child)
+
+	tests := map[string]string{
+		"/tenants/acme/admin/users/bob": "tenant=acme&user=bob",
+		"/tenants/acme/admin":           "tenant=acme",
+		"/tenants/acme/admin/missing":   "child not found",
+	}
+	for path, expected := range tests {
+		if body := serveBody(router, httpGet, path); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+
+	recorder := servePath(router, "OPTIONS", "/tenants/acme/admin/users/bob")
+	if allow := recorder.Header().Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+		t.Errorf("Test failed: Expected the mounted router's Allow header and received %s.", allow)
+	}
+	recorder = servePath(router, "HEAD", "/tenants/acme/admin/users/bob")
+	if recorder.Code != http.StatusOK || recorder.Body.Len() != 0 {
+		t.Errorf("Test failed: Expected an empty 200 and received %d %s.", recorder.Code, recorder.Body.String())
+	}
+}
+
+// TestGroupMount checks that a group mounts under its prefix, wrapped in its middlewares.
+func TestGroupMount(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api", tagMiddleware("api "))
+	api.Mount("/legacy", http.HandlerFunc(echoRequestHandler))
+
+	if body := serveBody(router, httpPost, "/api/legacy/orders"); body != "api POST /orders?" {
+		t.Errorf("Test failed: Expected api POST /orders? and received %s.", body)
+	}
+}
+
+// TestMountPrecedence checks that a mount competes with the routes of the request method by path precedence, so a
+// single page app served at the root by a catch-all doesn't take the requests of an API mounted next to it.
+func TestMountPrecedence(t *testing.T) {
+	api := NewRouter()
+	api.AddRoute(httpGet, "/users/:user", echoParams)
+
+	router := NewRouter()
+	router.ServeFS("/", fstest.MapFS{"index.html": {Data: []byte("app")}, "api.txt": {Data: []byte("file")}})
+	router.Mount("/api", api)
+	router.AddRoute(httpGet, "/api/status", writeHandler("ok"))
+	router.AddRoute(httpGet, "/:page/about", echoParams)
+	router.Mount("/help", http.HandlerFunc(echoRequestHandler))
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{httpGet, "/api/users/bob", "user=bob"},
+		{"HEAD", "/api/users/bob", ""},
+		{httpGet, "/api", "404 page not found\n"},
+		{httpGet, "/api/status", "ok"},
+		{httpGet, "/api.txt", "file"},
+		{httpGet, "/", "app"},
+		{httpGet, "/help/about", "GET /about?"},
+		{httpGet, "/team/about", "page=team"},
+	}
+	for _, test := range tests {
+		if body := serveBody(router, test.method, test.path); body != test.expected {
+			t.Errorf("Test failed: Expected %q for %s %s and received %q.", test.expected, test.method, test.path,
+				body)
+		}
+	}
+}
+
+// TestMountRedirect checks that the redirects of a mounted router keep the prefix it is mounted at, including the
+// prefixes of the mounts it is nested in and the values they captured.
+func TestMountRedirect(t *testing.T) {
+	child := NewRouter()
+	child.PathPolicy = RedirectPath
+	child.CasePolicy = RedirectCase
+	child.AddRoute(httpGet, "/users", echoParams)
+
+	tenants := NewRouter()
+	tenants.Mount("/tenants/:tenant/admin", child)
+
+	router := NewRouter()
+	router.Mount("/admin", child)
+	router.Mount("/v1", tenants)
+	router.Mount("/", child)
+
+	tests := map[string]string{
+		"/admin/users/":                  "/admin/users?page=2",
+		"/admin/USERS":                   "/admin/users?page=2",
+		"/v1/tenants/acme/admin/Users/":  "/v1/tenants/acme/admin/users?page=2",
+		"/v1/tenants/acme/admin/./users": "/v1/tenants/acme/admin/users?page=2",
+		"/users/":                        "/users?page=2",
+	}
+	for path, expected := range tests {
+		recorder := servePath(router, httpGet, path)
+		if location := recorder.Header().Get("Location"); recorder.Code != http.StatusMovedPermanently ||
+			location != expected {
+			t.Errorf("Test failed: Expected a 301 to %s for %s and received %d %s.", expected, path, recorder.Code,
+				location)
+		}
+	}
+}
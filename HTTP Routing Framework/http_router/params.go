@@ -0,0 +1,34 @@
+/*****************************************************************************
+ * params.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// paramsKey is the request context key the captured values of the matched route are stored under
+type paramsKey struct{}
+
+// Params returns the values captured from the request path by the matched route. Values captured under the same name
+// appear in the order they did in the request path, e.g. the pattern `/path/to/:file/:file` serving
+// `/path/to/a/b` gives file=[a b]. The returned values are shared with the request and should not be modified.
+func Params(request *http.Request) url.Values {
+	values, _ := request.Context().Value(paramsKey{}).(url.Values)
+	return values
+}
+
+// Param returns the first value captured under name from the request path, or "" if there is none
+func Param(request *http.Request, name string) string {
+	return Params(request).Get(name)
+}
+
+// withParams returns a shallow copy of request carrying the captured values in its context
+func withParams(request *http.Request, values url.Values) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), paramsKey{}, values))
+}
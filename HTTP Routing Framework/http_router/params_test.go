@@ -0,0 +1,53 @@
+/******************************************************************************
+ *  params_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for reading captured values through Params.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestParamsKeepQuery checks that captures are available through Params while the client's query string is kept.
+func TestParamsKeepQuery(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user/recent", func(response http.ResponseWriter, request *http.Request) {
+		response.Write([]byte(Param(request, "user") + " " + request.URL.RawQuery))
+	})
+
+	body := serveBody(router, httpGet, "/users/cesar/recent?page=2")
+	if body != "cesar page=2" {
+		t.Errorf("Test failed: Expected %s and received %s.", "cesar page=2", body)
+	}
+}
+
+// TestParamsRepeated checks that values captured under the same name keep the order of the request path.
+func TestParamsRepeated(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/path/to/:file/:dir/:file", echoParams)
+
+	body := serveBody(router, httpGet, "/path/to/b/x/a")
+	if body != "dir=x&file=b&file=a" {
+		t.Errorf("Test failed: Expected %s and received %s.", "dir=x&file=b&file=a", body)
+	}
+}
+
+// TestParamsLegacyRawQuery checks that LegacyRawQuery still replaces the raw query with the captured values.
+func TestParamsLegacyRawQuery(t *testing.T) {
+	router := NewRouter()
+	router.LegacyRawQuery = true
+	router.AddRoute(httpGet, "/users/:user/recent", echoPathCaptures)
+
+	body := serveBody(router, httpGet, "/users/cesar/recent?page=2")
+	if body != "user=cesar" {
+		t.Errorf("Test failed: Expected %s and received %s.", "user=cesar", body)
+	}
+}
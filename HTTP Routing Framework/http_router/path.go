@@ -0,0 +1,120 @@
+/*****************************************************************************
+ * path.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// PathPolicy decides how the router treats request paths that differ from the canonical path of a route, which is
+// its pattern as added to the router: with a leading '/', and with a trailing '/' only if the pattern was added with
+// one. `/a/b/` is the canonical path of a route added as `a/b/`, and `/` of the route added as `/`. The canonical path
+// of a pattern ending with a catch-all keeps the trailing '/' of the request path, if any.
+type PathPolicy int
+
+const (
+	// TrimSlashes ignores a leading and a trailing '/' of the request path, so `/a/b/` and `/a/b` reach the same
+	// route while `/a//b` and `/a/./b` reach none. It is the default policy.
+	TrimSlashes PathPolicy = iota
+	// StrictPath only routes requests whose path is the canonical path of a route
+	StrictPath
+	// RedirectPath cleans the request path as CleanPath does and redirects requests whose path is not the canonical
+	// path of their route to it, with a 301 for GET and HEAD requests and a 308, which keeps the method and body, for
+	// any other request
+	RedirectPath
+	// CleanPath collapses repeated '/' and resolves `.` and `..` directories of the request path before routing it,
+	// ignoring a trailing '/' as TrimSlashes does
+	CleanPath
+)
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// splitPath returns the directories of requestPath the router looks up routes for under the policy
+func (policy PathPolicy) splitPath(requestPath string) []string {
+	if policy == RedirectPath || policy == CleanPath {
+		requestPath = cleanPath(requestPath)
+	}
+	return splitPattern(trimPattern(requestPath))
+}
+
+// cleanPath returns requestPath rooted at '/' with repeated '/' collapsed and `.` and `..` directories resolved.
+// `..` directories never go above the root.
+func cleanPath(requestPath string) string {
+	return path.Clean("/" + requestPath)
+}
+
+// canonicalPath returns the canonical path of route for a request with the given path and directories, with static
+// directories cased as they were added and captured directories as they were requested
+func (route *RoutesField) canonicalPath(requestPath string, requestSegments []string) string {
+	if len(requestSegments) == 0 {
+		return "/"
+	}
+	if route.staticCaseDiffers(requestSegments) {
+		requestSegments = slices.Clone(requestSegments)
+		for i, segment := range route.segments {
+			if !isCapture(segment) && !isCatchAll(segment) {
+				requestSegments[i] = segment
+			}
+		}
+	}
+	canonical := "/" + strings.Join(requestSegments, "/")
+	if route.trailingSlash || (route.endsWithCatchAll() && strings.HasSuffix(requestPath, "/")) {
+		canonical += "/"
+	}
+	return canonical
+}
+
+// endsWithCatchAll reports whether the pattern of route ends with a catch-all
+func (route *RoutesField) endsWithCatchAll() bool {
+	return len(route.segments) > 0 && isCatchAll(route.segments[len(route.segments)-1])
+}
+
+// matchPath finds the route of the given method for a request, its host labels, and its path directories split by the
+// PathPolicy. Under StrictPath a route only matches if the request path is its canonical path.
+func (router *HTTPRouter) matchPath(table *routeTable, request *http.Request, hostLabels []string, method string,
+	requestSegments []string) *RoutesField {
+	requestPath := request.URL.Path
+	route := table.match(request, hostLabels, method, requestSegments, router.CasePolicy != MatchCase)
+	if route == nil || router.PathPolicy != StrictPath {
+		return route
+	}
+	// the case of static directories is left to the CasePolicy
+	canonical := route.canonicalPath(requestPath, requestSegments)
+	if requestPath != canonical && (router.CasePolicy == MatchCase || !strings.EqualFold(requestPath, canonical)) {
+		return nil
+	}
+	return route
+}
+
+// canonicalRedirect returns a handler redirecting the request to the canonical path of its route, keeping its query,
+// or nil if the request should be served by its route. The prefix stripped by Mount, if the router is mounted, is put
+// back on the location. The redirect never leaves the site, as repeated leading '/' of the location are collapsed.
+func (router *HTTPRouter) canonicalRedirect(request *http.Request, requestSegments []string,
+	route *RoutesField) http.Handler {
+	redirectCase := router.CasePolicy == RedirectCase && route.staticCaseDiffers(requestSegments)
+	if router.PathPolicy != RedirectPath && !redirectCase {
+		return nil
+	}
+	canonical := route.canonicalPath(request.URL.Path, requestSegments)
+	if request.URL.Path == canonical {
+		return nil
+	}
+
+	status := http.StatusPermanentRedirect
+	if request.Method == http.MethodGet || request.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+	// an empty first directory, as a capture can match, would make the location `//host/...`, which clients read as a
+	// link to another site
+	canonical = "/" + strings.TrimLeft(mountPrefix(request)+canonical, "/")
+	location := (&url.URL{Path: canonical, RawQuery: request.URL.RawQuery}).String()
+	return http.RedirectHandler(location, status)
+}
@@ -0,0 +1,165 @@
+/******************************************************************************
+ *  path_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for the policies routing request paths that differ from the canonical
+ *    path of their route.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// newPathRouter creates a router with the given policy and routes with and without trailing slashes
+func newPathRouter(policy PathPolicy) *HTTPRouter {
+	router := NewRouter()
+	router.PathPolicy = policy
+	router.AddRoute(httpGet, "/", echoPathHandler)
+	router.AddRoute(httpGet, "/a/b", echoPathHandler)
+	router.AddRoute(httpPost, "/a/b", echoMethodHandler)
+	router.AddRoute(httpGet, "/dirs/:dir/", echoParams)
+	return router
+}
+
+// servePath sends a single request for path to router, with the headers given as name, value pairs, and returns the
+// recorded response. The query is "page=2" unless path has its own. A Host header sets the host of the request, as it
+// does for requests read by a server.
+func servePath(router http.Handler, method string, path string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, "http://localhost:8080/", nil)
+	request.URL.Path = path
+	request.URL.RawQuery = "page=2"
+	if path, query, ok := strings.Cut(path, "?"); ok {
+		request.URL.Path, request.URL.RawQuery = path, query
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		if headers[i] == "Host" {
+			request.Host = headers[i+1]
+		} else {
+			request.Header.Set(headers[i], headers[i+1])
+		}
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestTrimSlashes checks that the default policy keeps ignoring a single leading and trailing slash only.
+func TestTrimSlashes(t *testing.T) {
+	router := newPathRouter(TrimSlashes)
+	tests := map[string]int{
+		"/a/b":      http.StatusOK,
+		"/a/b/":     http.StatusOK,
+		"a/b":       http.StatusOK,
+		"/dirs/x":   http.StatusOK,
+		"/a//b":     http.StatusNotFound,
+		"/a/./b":    http.StatusNotFound,
+		"/a/c/../b": http.StatusNotFound,
+	}
+	for path, expected := range tests {
+		if recorder := servePath(router, httpGet, path); recorder.Code != expected {
+			t.Errorf("Test failed: Expected %d for %s and received %d.", expected, path, recorder.Code)
+		}
+	}
+}
+
+// TestStrictPath checks that only the canonical path of a route is routed, for every method.
+func TestStrictPath(t *testing.T) {
+	router := newPathRouter(StrictPath)
+	tests := map[string]int{
+		"/":          http.StatusOK,
+		"/a/b":       http.StatusOK,
+		"/dirs/x/":   http.StatusOK,
+		"/a/b/":      http.StatusNotFound,
+		"/dirs/x":    http.StatusNotFound,
+		"/a//b":      http.StatusNotFound,
+		"//":         http.StatusNotFound,
+		"/a/b/../b":  http.StatusNotFound,
+		"/dirs/x//":  http.StatusNotFound,
+		"/dirs/./x/": http.StatusNotFound,
+	}
+	for path, expected := range tests {
+		if recorder := servePath(router, httpGet, path); recorder.Code != expected {
+			t.Errorf("Test failed: Expected %d for %s and received %d.", expected, path, recorder.Code)
+		}
+	}
+
+	if recorder := servePath(router, "DELETE", "/a/b/"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Test failed: Expected a 404 instead of a 405 and received %d.", recorder.Code)
+	}
+}
+
+// TestRedirectPath checks that non-canonical paths are redirected to the canonical path with their query, with a
+// 301 for GET and HEAD requests and a 308 otherwise, while canonical paths are served.
+func TestRedirectPath(t *testing.T) {
+	router := newPathRouter(RedirectPath)
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{httpGet, "/a/b", http.StatusOK, ""},
+		{httpGet, "/a/b/", http.StatusMovedPermanently, "/a/b?page=2"},
+		{httpGet, "/a//b", http.StatusMovedPermanently, "/a/b?page=2"},
+		{httpGet, "/x/../a/./b", http.StatusMovedPermanently, "/a/b?page=2"},
+		{"HEAD", "/a/b/", http.StatusMovedPermanently, "/a/b?page=2"},
+		{httpPost, "/a/b/", http.StatusPermanentRedirect, "/a/b?page=2"},
+		{httpGet, "/dirs/x", http.StatusMovedPermanently, "/dirs/x/?page=2"},
+		{httpGet, "/dirs/a b/", http.StatusOK, ""},
+		{httpGet, "/dirs/a b", http.StatusMovedPermanently, "/dirs/a%20b/?page=2"},
+		{httpGet, "//", http.StatusMovedPermanently, "/?page=2"},
+		{httpGet, "/../", http.StatusMovedPermanently, "/?page=2"},
+	}
+	for _, test := range tests {
+		recorder := servePath(router, test.method, test.path)
+		if recorder.Code != test.status || recorder.Header().Get("Location") != test.location {
+			t.Errorf("Test failed: Expected %d %s for %s %s and received %d %s.", test.status, test.location,
+				test.method, test.path, recorder.Code, recorder.Header().Get("Location"))
+		}
+	}
+}
+
+// TestCleanPath checks that cleaned paths are served directly with the captures of the cleaned path.
+func TestCleanPath(t *testing.T) {
+	router := newPathRouter(CleanPath)
+	tests := map[string]string{
+		"/a//b":          "/a//b",
+		"/a/./b/":        "/a/./b/",
+		"/dirs/x/../y/":  "dir=y",
+		"/../../dirs/z":  "dir=z",
+		"/dirs//nested/": "dir=nested",
+	}
+	for path, expected := range tests {
+		if body := servePath(router, httpGet, path).Body.String(); body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+}
+
+// TestGroupTrailingSlash checks that a group keeps the trailing slash of its route patterns for the path policy.
+func TestGroupTrailingSlash(t *testing.T) {
+	router := NewRouter()
+	router.PathPolicy = StrictPath
+	api := router.Group("/api/")
+	api.AddRoute(httpGet, "/users/", echoPathHandler)
+	api.AddRoute(httpGet, "/", echoPathHandler)
+
+	for path, expected := range map[string]int{"/api/users/": 200, "/api/users": 404, "/api": 200, "/api/": 404} {
+		if recorder := servePath(router, httpGet, path); recorder.Code != expected {
+			t.Errorf("Test failed: Expected %d for %s and received %d.", expected, path, recorder.Code)
+		}
+	}
+}
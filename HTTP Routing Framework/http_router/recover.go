@@ -0,0 +1,165 @@
+/*****************************************************************************
+ * recover.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is the error a recovered panic is reported and answered with
+type PanicError struct {
+	Method  string // method of the request
+	Pattern string // pattern of the matched route with its leading slash, or empty if the request had no route
+	Value   any    // value the handler panicked with
+	Stack   []byte // stack trace of the panicking goroutine
+}
+
+// Error describes the panic, without its stack trace
+func (err *PanicError) Error() string {
+	if err.Pattern == "" {
+		return fmt.Sprintf("http_router: panic serving %s without a route: %v", err.Method, err.Value)
+	}
+	return fmt.Sprintf("http_router: panic serving %s %s: %v", err.Method, err.Pattern, err.Value)
+}
+
+// Unwrap returns the value the handler panicked with if it is an error
+func (err *PanicError) Unwrap() error {
+	if wrapped, ok := err.Value.(error); ok {
+		return wrapped
+	}
+	return nil
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// recoverer wraps handler, serving the given route or a fallback if route is nil, so that a panic is logged, passed
+// to OnPanic, and answered with a 500 through the Error fallback. If the response was already started it can't be
+// answered, so the recoverer panics with http.ErrAbortHandler to have net/http abort it, which lets the client see the
+// response failed rather than take it as complete. http.ErrAbortHandler is panicked again too, since it is how
+// handlers ask net/http to abort the response.
+func (router *HTTPRouter) recoverer(handler http.Handler, route *RoutesField) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		tracked, wrapped := trackResponse(response)
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err := &PanicError{Method: request.Method, Value: recovered, Stack: debug.Stack()}
+			if route != nil {
+				err.Pattern = "/" + route.Pattern
+			}
+			log.Printf("%v\n%s", err, err.Stack)
+			if router.OnPanic != nil {
+				router.OnPanic(request, err)
+			}
+			if tracked.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			router.WriteError(response, request, http.StatusInternalServerError, err)
+		}()
+		handler.ServeHTTP(wrapped, request)
+	})
+}
+
+// trackingResponseWriter is a response writer that records whether the response was started
+type trackingResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// trackResponse returns a tracking response writer for response, and the writer to hand the handler, which also
+// implements http.Hijacker and io.ReaderFrom when response does, so that handlers asserting them keep working
+func trackResponse(response http.ResponseWriter) (*trackingResponseWriter, http.ResponseWriter) {
+	tracked := &trackingResponseWriter{ResponseWriter: response}
+	_, hijacks := response.(http.Hijacker)
+	_, readsFrom := response.(io.ReaderFrom)
+	switch {
+	case hijacks && readsFrom:
+		return tracked, hijackingReaderFromWriter{tracked}
+	case hijacks:
+		return tracked, hijackingWriter{tracked}
+	case readsFrom:
+		return tracked, readerFromWriter{tracked}
+	default:
+		return tracked, tracked
+	}
+}
+
+// WriteHeader records that the response was started before writing its header
+func (response *trackingResponseWriter) WriteHeader(status int) {
+	response.wroteHeader = true
+	response.ResponseWriter.WriteHeader(status)
+}
+
+// Write records that the response was started before writing body
+func (response *trackingResponseWriter) Write(body []byte) (int, error) {
+	response.wroteHeader = true
+	return response.ResponseWriter.Write(body)
+}
+
+// Flush records that the response was started before flushing it, if the wrapped response writer supports flushing
+func (response *trackingResponseWriter) Flush() {
+	if flusher, ok := response.ResponseWriter.(http.Flusher); ok {
+		response.wroteHeader = true
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the wrapped response writer, so that http.ResponseController can reach it
+func (response *trackingResponseWriter) Unwrap() http.ResponseWriter {
+	return response.ResponseWriter
+}
+
+// hijack records that the connection was taken over, after which the router can't answer the request, before
+// hijacking it from the wrapped response writer, which must implement http.Hijacker
+func (response *trackingResponseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	response.wroteHeader = true
+	return response.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// readFrom records that the response was started before copying reader to the wrapped response writer, which must
+// implement io.ReaderFrom
+func (response *trackingResponseWriter) readFrom(reader io.Reader) (int64, error) {
+	response.wroteHeader = true
+	return response.ResponseWriter.(io.ReaderFrom).ReadFrom(reader)
+}
+
+// hijackingWriter is a tracking response writer for response writers implementing http.Hijacker
+type hijackingWriter struct{ *trackingResponseWriter }
+
+func (response hijackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return response.hijack()
+}
+
+// readerFromWriter is a tracking response writer for response writers implementing io.ReaderFrom
+type readerFromWriter struct{ *trackingResponseWriter }
+
+func (response readerFromWriter) ReadFrom(reader io.Reader) (int64, error) {
+	return response.readFrom(reader)
+}
+
+// hijackingReaderFromWriter is a tracking response writer for response writers implementing both http.Hijacker and
+// io.ReaderFrom, as those of net/http do
+type hijackingReaderFromWriter struct{ *trackingResponseWriter }
+
+func (response hijackingReaderFromWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return response.hijack()
+}
+func (response hijackingReaderFromWriter) ReadFrom(reader io.Reader) (int64, error) {
+	return response.readFrom(reader)
+}
@@ -0,0 +1,197 @@
+/******************************************************************************
+ *  recover_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for recovering from panicking handlers and middlewares.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// captureLog redirects the standard logger to a buffer until the test ends
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buffer bytes.Buffer
+	log.SetOutput(&buffer)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buffer
+}
+
+// panicHandler panics with an error, without writing a response
+func panicHandler(response http.ResponseWriter, request *http.Request) {
+	panic(errors.New("boom"))
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestRecoverPanic checks that a panicking handler is answered with a 500 and that the panic is logged with the
+// method, the matched pattern and the stack, and reported to OnPanic.
+func TestRecoverPanic(t *testing.T) {
+	logged := captureLog(t)
+	router := NewRouter()
+	router.AddRoute(httpGet, "/boom/:id", panicHandler)
+	var reported *PanicError
+	router.OnPanic = func(request *http.Request, err *PanicError) {
+		reported = err
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(httpGet, "http://localhost:8080/boom/1", nil))
+
+	if recorder.Code != http.StatusInternalServerError || recorder.Body.String() != "500 internal server error\n" {
+		t.Errorf("Test failed: Expected a 500 and received %d %q.", recorder.Code, recorder.Body.String())
+	}
+	if reported == nil || reported.Pattern != "/boom/:id" || reported.Method != httpGet {
+		t.Fatalf("Test failed: Expected the panic to be reported with its route and received %+v.", reported)
+	}
+	if reported.Unwrap() == nil || reported.Unwrap().Error() != "boom" {
+		t.Errorf("Test failed: Expected the panic value to be unwrapped and received %v.", reported.Unwrap())
+	}
+	message := logged.String()
+	if !strings.Contains(message, "panic serving GET /boom/:id: boom") || !strings.Contains(message, "goroutine") {
+		t.Errorf("Test failed: Expected the panic and its stack to be logged and received %s.", message)
+	}
+}
+
+// TestRecoverPanicAfterWrite checks that a response already started by the panicking handler is aborted, so that
+// the client sees it fail instead of taking the truncated body as complete.
+func TestRecoverPanicAfterWrite(t *testing.T) {
+	logged := captureLog(t)
+	router := NewRouter()
+	router.AddRoute(httpGet, "/partial", func(response http.ResponseWriter, request *http.Request) {
+		response.WriteHeader(http.StatusAccepted)
+		response.Write([]byte("first half of a json array ["))
+		response.(http.Flusher).Flush()
+		panic("boom")
+	})
+	reported := make(chan struct{})
+	router.OnPanic = func(request *http.Request, err *PanicError) { close(reported) }
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/partial")
+	if err != nil {
+		t.Fatalf("Test failed: Expected the response to start and received %v.", err)
+	}
+	defer response.Body.Close()
+	if _, err := io.ReadAll(response.Body); err == nil {
+		t.Errorf("Test failed: Expected reading the aborted body to fail.")
+	}
+	<-reported
+	if !strings.Contains(logged.String(), "panic serving GET /partial: boom") {
+		t.Errorf("Test failed: Expected the panic to be logged and received %s.", logged)
+	}
+}
+
+// TestRecoverOptionalInterfaces checks that handlers served with panic recovery, and error-returning handlers, still
+// see the optional interfaces of the response writer, and only those it implements.
+func TestRecoverOptionalInterfaces(t *testing.T) {
+	interfaces := func(response http.ResponseWriter, request *http.Request) {
+		_, hijacks := response.(http.Hijacker)
+		_, readsFrom := response.(io.ReaderFrom)
+		fmt.Fprintf(response, "%t %t", hijacks, readsFrom)
+	}
+	router := NewRouter()
+	router.AddRoute(httpGet, "/plain", interfaces)
+	router.AddErrorRoute(httpGet, "/error", func(response http.ResponseWriter, request *http.Request) error {
+		interfaces(response, request)
+		return nil
+	})
+	router.AddRoute(httpGet, "/hijack", func(response http.ResponseWriter, request *http.Request) {
+		conn, buffer, err := response.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Test failed: Expected the connection to be hijacked and received %v.", err)
+			return
+		}
+		defer conn.Close()
+		buffer.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 6\r\nConnection: close\r\n\r\nraw ok")
+		buffer.Flush()
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	for path, expected := range map[string]string{"/plain": "true true", "/error": "true true", "/hijack": "raw ok"} {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Test failed: Expected a response for %s and received %v.", path, err)
+		}
+		body := responseBodyToString(response)
+		response.Body.Close()
+		if body != expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s.", expected, path, body)
+		}
+	}
+
+	if body := serveBody(router, httpGet, "/plain"); body != "false false" {
+		t.Errorf("Test failed: Expected a recorder to implement neither and received %s.", body)
+	}
+}
+
+// TestRecoverPanicFallbacks checks that the 500 is written through the Error fallback of the request's group, and
+// that panics in router middlewares on requests without a route are recovered too.
+func TestRecoverPanicFallbacks(t *testing.T) {
+	logged := captureLog(t)
+	router := NewRouter()
+	api := router.Group("/api")
+	api.Error = ProblemJSON
+	api.AddRoute(httpGet, "/boom", panicHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(httpGet, "http://localhost:8080/api/boom", nil))
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("Test failed: Expected application/problem+json and received %s.", contentType)
+	}
+
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(panicHandler)
+	})
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(httpGet, "http://localhost:8080/missing", nil))
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Test failed: Expected a 500 and received %d.", recorder.Code)
+	}
+	if !strings.Contains(logged.String(), "panic serving GET without a route: boom") {
+		t.Errorf("Test failed: Expected the panic to be logged without a route and received %s.", logged.String())
+	}
+}
+
+// TestRecoverPanicsDisabled checks that panics reach net/http when recovery is turned off, and that
+// http.ErrAbortHandler always does.
+func TestRecoverPanicsDisabled(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/boom", panicHandler)
+	router.AddRoute(httpGet, "/abort", func(response http.ResponseWriter, request *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	servePanics := func(path string) (recovered any) {
+		defer func() { recovered = recover() }()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(httpGet, "http://localhost:8080"+path, nil))
+		return nil
+	}
+
+	if recovered := servePanics("/abort"); recovered != http.ErrAbortHandler {
+		t.Errorf("Test failed: Expected http.ErrAbortHandler to be panicked again and received %v.", recovered)
+	}
+	router.RecoverPanics = false
+	if recovered := servePanics("/boom"); recovered == nil {
+		t.Errorf("Test failed: Expected the panic to reach the caller.")
+	}
+}
@@ -0,0 +1,60 @@
+/*****************************************************************************
+ * remove.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import "strings"
+
+// RemoveRoute removes the route of the given method whose pattern has the same shape as pattern, matched by the same
+// rules AddRoute uses to replace routes, so `/photos/:place/picture` removes a route added as `/photos/:year/picture`.
+// Routes of that shape added with different matchers are all removed. It returns whether a route was removed.
+func (router *HTTPRouter) RemoveRoute(method string, pattern string) bool {
+	return router.removeRoute("", method, pattern)
+}
+
+// removeRoute is a helper function for RemoveRoute that removes a route scoped to the given host pattern, or a route
+// without a host if host is empty
+func (router *HTTPRouter) removeRoute(host string, method string, pattern string) bool {
+	host = normalizeHost(host)
+	method = strings.ToUpper(method)
+	segments := splitPattern(trimPattern(pattern))
+
+	removed := false
+	router.updateTable(func(table *routeTable) error {
+		removed = table.removeRoutes(host, method, segments)
+		return nil
+	})
+	return removed
+}
+
+// ReplaceRoutes replaces every route of the router with routes in a single step, so that requests see either all of
+// the old routes or all of the new ones. Middlewares and groups are kept. Each route is added as AddRoute would add
+// it, with its Middlewares wrapping its Handler. If two of the routes have the same method and shape, a ConflictError
+// is returned and the router is left unchanged.
+func (router *HTTPRouter) ReplaceRoutes(routes []RoutesField) error {
+	prepared := make([]*RoutesField, len(routes))
+	for i, route := range routes {
+		prepared[i] = prepareRoute(route)
+	}
+
+	return router.updateTable(func(table *routeTable) error {
+		table.trees = map[string]*node{}
+		table.hosts = nil
+		table.names = map[string]*RoutesField{}
+		for _, route := range prepared {
+			if err := table.addRoute(route, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveRoute removes the route of the given method whose pattern, with the group prefix prepended, has the same
+// shape as pattern, among the routes scoped to the group's host
+func (group *Group) RemoveRoute(method string, pattern string) bool {
+	return group.router.removeRoute(group.hostPattern(), method, group.fullPattern(pattern))
+}
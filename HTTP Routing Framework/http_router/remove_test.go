@@ -0,0 +1,142 @@
+/******************************************************************************
+ *  remove_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for removing routes and replacing the whole route table.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestRemoveRoute checks that a route is removed by any pattern of the same shape, while routes that only share a
+// prefix or differ in method are kept.
+func TestRemoveRoute(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/photos/:year/picture", echoParams, WithName("picture"))
+	router.AddRoute(httpGet, "/photos/:year/july", echoPathHandler)
+	router.AddRoute(httpPost, "/photos/:year/picture", echoMethodHandler)
+
+	if !router.RemoveRoute("get", "photos/:place/picture/") {
+		t.Fatalf("Test failed: Expected the route to be removed.")
+	}
+	if router.RemoveRoute(httpGet, "/photos/:place/picture") {
+		t.Errorf("Test failed: Expected nothing left to remove.")
+	}
+	if body := serveBody(router, httpPost, "/photos/2020/picture"); body != "POST" {
+		t.Errorf("Test failed: Expected POST and received %s.", body)
+	}
+	if body := serveBody(router, httpGet, "/photos/2020/july"); body != "/photos/2020/july" {
+		t.Errorf("Test failed: Expected /photos/2020/july and received %s.", body)
+	}
+	if _, err := router.URL("picture", "year", "2020"); err == nil {
+		t.Errorf("Test failed: Expected the name of the removed route to be released.")
+	}
+	if len(router.Routes()) != 2 {
+		t.Errorf("Test failed: Expected 2 routes and found %d.", len(router.Routes()))
+	}
+}
+
+// TestRemoveRouteConstraint checks that a constrained capture is only removed by a pattern with the same constraint.
+func TestRemoveRouteConstraint(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:id<int>", echoParams)
+	router.AddRoute(httpGet, "/users/:name", echoParams)
+
+	if router.RemoveRoute(httpGet, "/users/:id<uuid>") {
+		t.Errorf("Test failed: Expected a differently constrained capture not to be removed.")
+	}
+	if !router.RemoveRoute(httpGet, "/users/:user<int>") {
+		t.Errorf("Test failed: Expected the int route to be removed.")
+	}
+	if body := serveBody(router, httpGet, "/users/42"); body != "name=42" {
+		t.Errorf("Test failed: Expected name=42 and received %s.", body)
+	}
+}
+
+// TestGroupRemoveRoute checks that a group removes routes relative to its prefix.
+func TestGroupRemoveRoute(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api/v1")
+	api.AddRoute(httpGet, "/users/:user", echoParams)
+
+	if !api.RemoveRoute(httpGet, "users/:id") {
+		t.Fatalf("Test failed: Expected the group route to be removed.")
+	}
+	if body := serveBody(router, httpGet, "/api/v1/users/alice"); body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %s.", body)
+	}
+}
+
+// TestReplaceRoutes checks that every route is swapped for the new routes while router middlewares are kept.
+func TestReplaceRoutes(t *testing.T) {
+	router := NewRouter()
+	router.Use(tagMiddleware("router"))
+	router.AddRoute(httpGet, "/old", echoPathHandler, WithName("old"))
+
+	err := router.ReplaceRoutes([]RoutesField{
+		{Method: "get", Pattern: "/new/:id/", Handler: echoParams, Name: "new"},
+		{Method: httpPost, Pattern: "/new", Handler: echoMethodHandler, Middlewares: []Middleware{tagMiddleware("route")}},
+	})
+	if err != nil {
+		t.Fatalf("Test failed: Expected no error and received %v.", err)
+	}
+
+	if body := serveBody(router, httpGet, "/old"); body != "router404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %q.", body)
+	}
+	if body := serveBody(router, httpGet, "/new/7"); body != "routerid=7" {
+		t.Errorf("Test failed: Expected routerid=7 and received %q.", body)
+	}
+	if body := serveBody(router, httpPost, "/new"); body != "routerroutePOST" {
+		t.Errorf("Test failed: Expected routerroutePOST and received %q.", body)
+	}
+	if url, err := router.URL("new", "id", "7"); err != nil || url != "/new/7/" {
+		t.Errorf("Test failed: Expected /new/7/ and received %s (%v).", url, err)
+	}
+	if _, err := router.URL("old"); err == nil {
+		t.Errorf("Test failed: Expected the old route name to be gone.")
+	}
+}
+
+// TestReplaceRoutesConflict checks that two new routes of the same shape leave the existing routes in place.
+func TestReplaceRoutesConflict(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/old", echoPathHandler)
+
+	err := router.ReplaceRoutes([]RoutesField{
+		{Method: httpGet, Pattern: "/users/:id", Handler: echoParams},
+		{Method: httpGet, Pattern: "/users/:name", Handler: echoParams},
+	})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Existing != "users/:id" {
+		t.Fatalf("Test failed: Expected a conflict with users/:id and received %v.", err)
+	}
+	if body := serveBody(router, httpGet, "/old"); body != "/old" {
+		t.Errorf("Test failed: Expected the old routes to be kept and received %s.", body)
+	}
+	if body := serveBody(router, httpGet, "/users/1"); body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %s.", body)
+	}
+}
+
+// TestReplaceRoutesEmpty checks that replacing with no routes leaves every path unrouted.
+func TestReplaceRoutesEmpty(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/", func(response http.ResponseWriter, request *http.Request) {})
+
+	if err := router.ReplaceRoutes(nil); err != nil {
+		t.Fatalf("Test failed: Expected no error and received %v.", err)
+	}
+	if len(router.Routes()) != 0 {
+		t.Errorf("Test failed: Expected no routes and found %d.", len(router.Routes()))
+	}
+}
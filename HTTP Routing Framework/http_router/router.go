@@ -10,54 +10,124 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // RoutesField fields the parameters needed to add a route
 type RoutesField struct {
-	Method  string
-	Pattern string
-	Handler http.HandlerFunc
+	Method        string
+	Pattern       string
+	Handler       http.HandlerFunc
+	Name          string    // optional name used to build URLs to the route with HTTPRouter.URL
+	Host          string    // optional host pattern the route is scoped to, see WithHost
+	Matchers      []Matcher // optional conditions requests must meet, see WithMatchers
+	Middlewares   []Middleware
+	handler       http.Handler // Handler wrapped in Middlewares
+	segments      []string     // directories of Pattern
+	hostLabels    []string     // labels of Host
+	trailingSlash bool         // whether Pattern was added with a trailing '/', used by the PathPolicy
 }
 
-// HTTPRouter stores a list of routes that contains: method, pattern, and handler that has been added
+// HTTPRouter stores the routes that have been added, each with its method, pattern, and handler, in a routing trie per
+// method used to find the route for a request. Routes can be added while requests are served: the routes are
+// published as an immutable table that requests read without locking, and each change publishes an updated copy.
+// The exported fields configure the router and should be set before it serves requests.
 type HTTPRouter struct {
-	Routes []RoutesField
+	Fallbacks
+	// PathPolicy decides how request paths that differ from the added patterns by slashes or dot directories are
+	// routed, by default a leading and a trailing '/' are ignored
+	PathPolicy PathPolicy
+	// CasePolicy decides whether static directories of patterns match request directories differing only in case, by
+	// default they don't
+	CasePolicy CasePolicy
+	// LegacyRawQuery also replaces the request's raw query with the captured values, as older handlers expect
+	LegacyRawQuery bool
+	// HandleMethodNotAllowed answers requests whose path only matches routes of other methods with a 405 listing
+	// those methods in its Allow header, instead of a 404
+	HandleMethodNotAllowed bool
+	// HandleHEAD serves HEAD requests without a HEAD route through the matching GET route, discarding the body
+	HandleHEAD bool
+	// HandleOPTIONS answers OPTIONS requests without an OPTIONS route with the methods the path is routed for
+	HandleOPTIONS bool
+	// RecoverPanics answers requests whose handler or middlewares panic with a 500 through the Error fallback, and
+	// logs the panic, instead of letting net/http drop the connection
+	RecoverPanics bool
+	// OnPanic, if set, is called with every panic recovered, after it is logged
+	OnPanic func(request *http.Request, err *PanicError)
+	// MapError, if set, maps the errors ErrorFunc handlers fail with to the status they are answered with in place of
+	// ErrorStatus
+	MapError ErrorMapper
+	table    atomic.Pointer[routeTable]
+	mutex    sync.Mutex // serializes updates to table
 }
 
 // NewRouter creates a new HTTP Router, with no initial routes
 func NewRouter() *HTTPRouter {
 	return &HTTPRouter{
-		Routes: []RoutesField{},
+		HandleMethodNotAllowed: true,
+		HandleHEAD:             true,
+		HandleOPTIONS:          true,
+		RecoverPanics:          true,
 	}
 }
 
+// Routes returns the routes that have been added, ordered as Walk orders them
+func (router *HTTPRouter) Routes() []RoutesField {
+	var routes []RoutesField
+	router.loadTable().walk(func(route *RoutesField) error {
+		routes = append(routes, *route)
+		return nil
+	})
+	return routes
+}
+
 //----------------------------------------------------------------------------------------------------------------------
 
-// AddRoute adds a new route to the router and maps a given method, path, and handler
-func (router *HTTPRouter) AddRoute(method string, pattern string, handler http.HandlerFunc) {
-	// Edge case: ignore leading and trailing '/'
-	if pattern == "/" {
-		pattern = ""
+// AddRoute adds a new route to the router and maps a given method, path, and handler, configured by any options
+func (router *HTTPRouter) AddRoute(method string, pattern string, handler http.HandlerFunc, options ...RouteOption) {
+	router.addRoute(method, pattern, handler, true, options)
+}
+
+// addRoute is a helper function for AddRoute and TryAddRoute that adds a route, replacing an existing route of the
+// same shape if replace is set, or returning a ConflictError otherwise
+func (router *HTTPRouter) addRoute(method string, pattern string, handler http.HandlerFunc, replace bool,
+	options []RouteOption) error {
+	route := RoutesField{Method: method, Pattern: pattern, Handler: handler}
+	for _, option := range options {
+		option(&route)
 	}
+	prepared := prepareRoute(route)
 
-	method = strings.ToUpper(method)
-	pattern = strings.TrimPrefix(pattern, "/")
-	pattern = strings.TrimSuffix(pattern, "/")
+	return router.updateTable(func(table *routeTable) error {
+		return table.addRoute(prepared, replace)
+	})
+}
 
-	for i := range router.Routes {
-		// checking for existing static patterns, updating handler accordingly
-		if router.Routes[i].Method == method && router.Routes[i].Pattern == pattern {
-			router.Routes[i].Handler = handler
-			return
-		}
-		// checking for existing dynamic patterns, updating handler and pattern accordingly
-		if router.Routes[i].Method == method && IsExistingPath(pattern, router.Routes[i].Pattern) {
-			router.Routes[i].Pattern = pattern
-			router.Routes[i].Handler = handler
-			return
-		}
+// prepareRoute is a helper function for addRoute and ReplaceRoutes that returns a copy of route ready to be added,
+// with its method in upper case, its pattern trimmed, and its handler wrapped in its middlewares
+func prepareRoute(route RoutesField) *RoutesField {
+	route.Method = strings.ToUpper(route.Method)
+	if route.Pattern != "/" && strings.HasSuffix(route.Pattern, "/") {
+		route.trailingSlash = true
 	}
-	router.Routes = append(router.Routes, RoutesField{Method: method, Pattern: pattern, Handler: handler})
+	route.Pattern = trimPattern(route.Pattern)
+	route.segments = splitPattern(route.Pattern)
+	if route.Host = normalizeHost(route.Host); route.Host != "" {
+		route.hostLabels = splitHost(route.Host)
+	}
+	route.handler = chain(route.Handler, route.Middlewares)
+	return &route
+}
+
+// trimPattern is a helper function for AddRoute and ServeHTTP that ignores a leading and a trailing '/'
+func trimPattern(pattern string) string {
+	// Edge case: "/" is the empty pattern
+	if pattern == "/" {
+		return ""
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	return strings.TrimSuffix(pattern, "/")
 }
 
 // IsExistingPath is a helper function for AddRoute that checks if an existing pattern in the router matches the new
@@ -75,7 +145,12 @@ func IsExistingPath(newPattern, existingPattern string) bool {
 	}
 
 	for i := range existingPathSplit {
-		if strings.HasPrefix(existingPathSplit[i], ":") && strings.HasPrefix(newPathSplit[i], ":") {
+		// captures are the same route if they have the same constraint, whatever their names
+		if isCapture(existingPathSplit[i]) && isCapture(newPathSplit[i]) &&
+			captureConstraint(existingPathSplit[i]) == captureConstraint(newPathSplit[i]) {
+			continue
+		}
+		if isCatchAll(existingPathSplit[i]) && isCatchAll(newPathSplit[i]) {
 			continue
 		}
 		if newPathSplit[i] != existingPathSplit[i] {
@@ -87,73 +162,120 @@ func IsExistingPath(newPattern, existingPattern string) bool {
 
 //----------------------------------------------------------------------------------------------------------------------
 
-// ServeHTTP For the given request, finds the correct handler
+// ServeHTTP For the given request, finds the correct handler and invokes it through the router's middlewares
 func (router *HTTPRouter) ServeHTTP(response http.ResponseWriter, request *http.Request) {
-	requestPattern := request.URL.Path
-	// Edge case: ignore leading and trailing '/'
-	if requestPattern == "/" {
-		requestPattern = ""
+	table := router.loadTable()
+	handler, request, route := router.findHandler(table, request)
+	handler = chain(handler, table.middlewares)
+	if router.RecoverPanics {
+		handler = router.recoverer(handler, route)
 	}
-	requestPattern = strings.TrimPrefix(requestPattern, "/")
-	requestPattern = strings.TrimSuffix(requestPattern, "/")
+	handler.ServeHTTP(response, request)
+}
+
+// findHandler returns the handler of the route matching the request, or the not found or method not allowed handler
+// if there is none. The returned request carries the captured values of the matched route, which is also returned,
+// or nil if no route matched. Routes of the request method are tried first, then for HEAD requests the GET routes.
+// The routes added by Mount for any method compete with the route found by path precedence, as IsHigherPrecedence
+// orders their patterns, and only serve the request if they take precedence over it.
+func (router *HTTPRouter) findHandler(table *routeTable, request *http.Request) (http.Handler, *http.Request,
+	*RoutesField) {
+	hostLabels := table.requestHostLabels(request)
+	requestSegments := router.PathPolicy.splitPath(request.URL.Path)
 
 	requestMethod := strings.ToUpper(request.Method)
 
-	var dynamicRoutes []RoutesField
-	var bestRoute RoutesField
-	bestRouteFound := false
+	method := requestMethod
+	bestRoute := router.matchPath(table, request, hostLabels, method, requestSegments)
+	if bestRoute == nil && requestMethod == http.MethodHead && router.HandleHEAD {
+		method = http.MethodGet
+		bestRoute = router.matchPath(table, request, hostLabels, method, requestSegments)
+	}
+	mounted := router.matchPath(table, request, hostLabels, anyMethod, requestSegments)
+	if mounted != nil && (bestRoute == nil || IsHigherPrecedence(mounted.Pattern, bestRoute.Pattern)) {
+		method, bestRoute = anyMethod, mounted
+	}
 
-	for _, route := range router.Routes {
-		// Checking for static routes FIRST and invoke the handler to write HTTP response
-		if route.Method == requestMethod && IsSameStaticPattern(requestPattern, route.Pattern) {
-			route.Handler(response, request)
-			return
+	if bestRoute != nil {
+		if redirect := router.canonicalRedirect(request, requestSegments, bestRoute); redirect != nil {
+			return redirect, request, nil
 		}
-		// Checking for dynamic routes and appending to slice
-		if route.Method == requestMethod && IsSameDynamicPattern(requestPattern, route.Pattern) {
-			dynamicRoutes = append(dynamicRoutes, route)
+		handler := bestRoute.handler
+		if method == http.MethodGet && requestMethod == http.MethodHead {
+			handler = headHandler(handler)
 		}
+		return handler, router.withCaptures(request, hostLabels, requestSegments, bestRoute), bestRoute
 	}
 
-	for _, route := range dynamicRoutes {
-		if !bestRouteFound || IsHigherPrecedence(route.Pattern, bestRoute.Pattern) {
-			bestRoute = route
-			bestRouteFound = true
-		}
+	allowed := router.allowedMethods(table, request, hostLabels, requestSegments)
+	if requestMethod == http.MethodOptions && router.HandleOPTIONS && len(allowed) > 0 {
+		return optionsHandler(allowed), request, nil
+	}
+	if router.HandleMethodNotAllowed && len(allowed) > 0 {
+		return router.methodNotAllowedHandler(table, hostLabels, requestSegments, allowed), request, nil
 	}
+	return router.notFoundHandler(table, hostLabels, requestSegments), request, nil
+}
 
-	if bestRouteFound {
-		captureToValues := GetCapturesValues(requestPattern, bestRoute.Pattern)
-		query := url.Values{}
-		for capture, valueSlice := range captureToValues {
-			for _, value := range valueSlice {
-				query.Add(capture, value)
-			}
+// withCaptures passes the values captured from the host and path by the matched route to its handler through the
+// request context. It maps captures to values like GetCapturesValues, reusing the directories already split for the
+// lookup.
+func (router *HTTPRouter) withCaptures(request *http.Request, hostLabels []string, requestSegments []string,
+	bestRoute *RoutesField) *http.Request {
+	if !strings.ContainsAny(bestRoute.Pattern, ":*") && !strings.Contains(bestRoute.Host, ":") {
+		return request
+	}
+	// values captured by the router a mounted router was mounted on come first
+	captures := url.Values{}
+	for name, values := range Params(request) {
+		captures[name] = append([]string(nil), values...)
+	}
+	addHostCaptures(captures, bestRoute, hostLabels)
+	for i, segment := range bestRoute.segments {
+		switch {
+		case isCapture(segment):
+			captures.Add(captureName(segment), requestSegments[i])
+		case isCatchAll(segment):
+			captures.Add(captureName(segment), strings.Join(requestSegments[i:], "/"))
 		}
-		request.URL.RawQuery = query.Encode()
-		bestRoute.Handler(response, request)
-		return
 	}
-	http.NotFound(response, request)
+	if router.LegacyRawQuery {
+		request.URL.RawQuery = captures.Encode()
+	}
+	return withParams(request, captures)
 }
 
 // IsHigherPrecedence is a helper function for ServeHTTP that compares two routes and finds out which pattern has
-// largest number of non-capturing path components to the left of its first capture.
+// largest number of non-capturing path components to the left of its first capture. At the same directory a static
+// directory beats a constrained capture, which beats a capture, which beats a catch-all.
 func IsHigherPrecedence(currentPattern, bestPattern string) bool {
 	currentPatternSplit := strings.Split(currentPattern, "/")
 	bestPatternSplit := strings.Split(bestPattern, "/")
 
 	for i := 0; i < len(currentPatternSplit) && i < len(bestPatternSplit); i++ {
-		if strings.HasPrefix(currentPatternSplit[i], ":") && !strings.HasPrefix(bestPatternSplit[i], ":") {
-			return false
-		}
-		if !strings.HasPrefix(currentPatternSplit[i], ":") && strings.HasPrefix(bestPatternSplit[i], ":") {
-			return true
+		currentRank, bestRank := captureRank(currentPatternSplit[i]), captureRank(bestPatternSplit[i])
+		if currentRank != bestRank {
+			return currentRank < bestRank
 		}
 	}
 	return len(currentPatternSplit) < len(bestPatternSplit)
 }
 
+// captureRank is a helper function for IsHigherPrecedence that ranks static directories, then constrained captures,
+// then captures, then catch-alls
+func captureRank(segment string) int {
+	switch {
+	case isCatchAll(segment):
+		return 3
+	case isCapture(segment) && captureConstraint(segment) == "":
+		return 2
+	case isCapture(segment):
+		return 1
+	default:
+		return 0
+	}
+}
+
 // IsSameStaticPattern is a helper function for ServeHTTP that checks if two static paths are the same
 func IsSameStaticPattern(requestPath string, routePath string) bool {
 	requestPathSplit := strings.Split(requestPath, "/")
@@ -182,6 +304,14 @@ func IsSameDynamicPattern(requestPath string, path string) bool {
 	// users/:user/recent -> users :user recent
 	pathSplit := strings.Split(path, "/")
 
+	// a trailing catch-all matches one or more remaining directories
+	if isCatchAll(pathSplit[len(pathSplit)-1]) {
+		if requestPath == "" || len(requestPathSplit) < len(pathSplit) {
+			return false
+		}
+		requestPathSplit = requestPathSplit[:len(pathSplit)]
+	}
+
 	if len(requestPathSplit) != len(pathSplit) {
 		return false
 	}
@@ -191,7 +321,13 @@ func IsSameDynamicPattern(requestPath string, path string) bool {
 	}
 
 	for i := range pathSplit {
-		if strings.HasPrefix(pathSplit[i], ":") {
+		if isCapture(pathSplit[i]) {
+			if !matchesConstraint(pathSplit[i], requestPathSplit[i]) {
+				return false
+			}
+			continue
+		}
+		if isCatchAll(pathSplit[i]) {
 			continue
 		}
 		if requestPathSplit[i] != pathSplit[i] {
@@ -212,9 +348,14 @@ func GetCapturesValues(requestPath string, path string) map[string][]string {
 
 	for i := range pathSplit {
 		if strings.HasPrefix(pathSplit[i], ":") {
-			capture := pathSplit[i][1:]
+			capture := captureName(pathSplit[i])
 			captureToValue[capture] = append(captureToValue[capture], requestPathSplit[i])
 		}
+		// a catch-all captures the rest of the request path
+		if isCatchAll(pathSplit[i]) {
+			capture := captureName(pathSplit[i])
+			captureToValue[capture] = append(captureToValue[capture], strings.Join(requestPathSplit[i:], "/"))
+		}
 	}
 	return captureToValue
 }
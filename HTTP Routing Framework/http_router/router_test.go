@@ -33,8 +33,9 @@ const (
 
 // An incomplete list of possible HTTP status codes. See http docs for more.
 const (
-	httpOK       = http.StatusOK       // 200
-	httpNotFound = http.StatusNotFound // 404
+	httpOK               = http.StatusOK               // 200
+	httpNotFound         = http.StatusNotFound         // 404
+	httpMethodNotAllowed = http.StatusMethodNotAllowed // 405
 )
 
 /******************************************************************************/
@@ -77,6 +78,13 @@ func echoPathCaptures(response http.ResponseWriter, request *http.Request) {
 	response.Write(captureBytes)
 }
 
+// echoParams writes the values captured by the matched route, encoded as a query string, to the response body, but
+// otherwise ignores all other request fields
+func echoParams(response http.ResponseWriter, request *http.Request) {
+	paramBytes := []byte(Params(request).Encode())
+	response.Write(paramBytes)
+}
+
 /******************************************************************************/
 /*                                  Tests                                     */
 /******************************************************************************/
@@ -136,3 +144,39 @@ func TestBasicPaths(t *testing.T) {
 		}
 	}
 }
+
+// TestMethodNotAllowed checks that a path only routed for other methods gets a 405 listing those methods, while an
+// unknown path still gets a 404.
+func TestMethodNotAllowed(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user", echoMethodHandler)
+	router.AddRoute("put", "/users/:user", echoMethodHandler)
+	router.AddRoute(httpPost, "/users", echoMethodHandler)
+
+	request := httptest.NewRequest(httpPost, "http://localhost:8080/users/cesar", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	response := recorder.Result()
+
+	if response.StatusCode != httpMethodNotAllowed {
+		t.Errorf("Test failed: Router gave non-405 status code: %d", response.StatusCode)
+	}
+	if allow := response.Header.Get("Allow"); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("Test failed: Expected Allow header %s and received %s.", "GET, HEAD, OPTIONS, PUT", allow)
+	}
+
+	request = httptest.NewRequest(httpPost, "http://localhost:8080/posts", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Result().StatusCode != httpNotFound {
+		t.Errorf("Test failed: Router gave non-404 status code: %d", recorder.Result().StatusCode)
+	}
+
+	router.HandleMethodNotAllowed = false
+	request = httptest.NewRequest(httpPost, "http://localhost:8080/users/cesar", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Result().StatusCode != httpNotFound {
+		t.Errorf("Test failed: Router gave non-404 status code: %d", recorder.Result().StatusCode)
+	}
+}
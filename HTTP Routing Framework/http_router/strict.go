@@ -0,0 +1,42 @@
+/*****************************************************************************
+ * strict.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ConflictError is returned by TryAddRoute when the route being added has the same shape as an existing route, as
+// decided by IsExistingPath, e.g. `/photos/:place/picture` and `/photos/:year/picture`
+type ConflictError struct {
+	Method   string
+	Host     string // host pattern both routes are scoped to, or empty
+	Pattern  string
+	Existing string
+}
+
+// Error describes the conflicting routes
+func (err *ConflictError) Error() string {
+	return fmt.Sprintf("http_router: route %s %s/%s conflicts with existing route %s %s/%s",
+		err.Method, err.Host, err.Pattern, err.Method, err.Host, err.Existing)
+}
+
+// TryAddRoute adds a route like AddRoute, but returns a ConflictError naming the existing route instead of silently
+// replacing it when a route of the same method and shape was already added
+func (router *HTTPRouter) TryAddRoute(method string, pattern string, handler http.HandlerFunc,
+	options ...RouteOption) error {
+	return router.addRoute(method, pattern, handler, false, options)
+}
+
+// MustAddRoute adds a route like TryAddRoute, but panics if it conflicts with an existing route
+func (router *HTTPRouter) MustAddRoute(method string, pattern string, handler http.HandlerFunc,
+	options ...RouteOption) {
+	if err := router.TryAddRoute(method, pattern, handler, options...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,54 @@
+/******************************************************************************
+ *  strict_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for adding routes without replacing conflicting routes.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestTryAddRouteConflict checks that a route of the same shape is reported instead of replacing the existing one.
+func TestTryAddRouteConflict(t *testing.T) {
+	router := NewRouter()
+	if err := router.TryAddRoute(httpGet, "/photos/:year/picture", echoParams); err != nil {
+		t.Fatalf("Test failed: Expected no error and received %v.", err)
+	}
+	if err := router.TryAddRoute(httpGet, "/photos/:place/winter", echoParams); err != nil {
+		t.Fatalf("Test failed: Expected coexisting routes and received %v.", err)
+	}
+
+	err := router.TryAddRoute("get", "photos/:place/picture", echoPathHandler)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Existing != "photos/:year/picture" {
+		t.Fatalf("Test failed: Expected a conflict with photos/:year/picture and received %v.", err)
+	}
+	if err.Error() != "http_router: route GET /photos/:place/picture conflicts with existing route GET /photos/:year/picture" {
+		t.Errorf("Test failed: Received unexpected message %s.", err)
+	}
+	if body := serveBody(router, httpGet, "/photos/2020/picture"); body != "year=2020" {
+		t.Errorf("Test failed: Expected the existing route to be kept and received %s.", body)
+	}
+}
+
+// TestMustAddRoutePanics checks that MustAddRoute panics on conflicts, including through groups.
+func TestMustAddRoutePanics(t *testing.T) {
+	router := NewRouter()
+	api := router.Group("/api")
+	api.MustAddRoute(httpGet, "/users/:user", echoParams)
+
+	defer func() {
+		if _, ok := recover().(*ConflictError); !ok {
+			t.Errorf("Test failed: Expected MustAddRoute to panic with a ConflictError.")
+		}
+	}()
+	router.MustAddRoute(httpGet, "/api/users/:name", echoParams)
+}
@@ -0,0 +1,155 @@
+/*****************************************************************************
+ * table.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import "net/http"
+
+// routeTable is a snapshot of everything registered on a router. A published table is never modified, so requests
+// can be served from it without locking while routes are added. Changes are made to a copy, which is then published
+// in its place.
+type routeTable struct {
+	trees       map[string]*node        // routing trie of each method, for the routes without a host
+	hosts       []*hostTable            // routes scoped to each host pattern, by precedence
+	names       map[string]*RoutesField // route of each route name
+	groups      []*Group
+	hostGroups  bool // whether some group is scoped to a host
+	middlewares []Middleware
+}
+
+// emptyTable is the table of a router nothing has been registered on
+var emptyTable = &routeTable{}
+
+// loadTable returns the router's current table
+func (router *HTTPRouter) loadTable() *routeTable {
+	if table := router.table.Load(); table != nil {
+		return table
+	}
+	return emptyTable
+}
+
+// updateTable applies change to a copy of the router's current table and publishes the copy, unless change returns an
+// error. Updates are serialized, so that no change made by a concurrent update is lost.
+func (router *HTTPRouter) updateTable(change func(table *routeTable) error) error {
+	router.mutex.Lock()
+	defer router.mutex.Unlock()
+
+	table := router.loadTable().clone()
+	if err := change(table); err != nil {
+		return err
+	}
+	router.table.Store(table)
+	return nil
+}
+
+// clone returns a copy of table whose maps and slices can be changed without changing table. The tries themselves are
+// shared, as they are copied along the changed pattern by node.set, and so are host tables until treesFor copies them.
+func (table *routeTable) clone() *routeTable {
+	copied := &routeTable{
+		trees:       make(map[string]*node, len(table.trees)),
+		hosts:       append([]*hostTable(nil), table.hosts...),
+		names:       make(map[string]*RoutesField, len(table.names)),
+		groups:      append([]*Group(nil), table.groups...),
+		hostGroups:  table.hostGroups,
+		middlewares: append([]Middleware(nil), table.middlewares...),
+	}
+	for method, root := range table.trees {
+		copied.trees[method] = root
+	}
+	for name, route := range table.names {
+		copied.names[name] = route
+	}
+	return copied
+}
+
+// addRoute adds a prepared route, replacing an existing route of the same shape and matchers if replace is set, or
+// returning a ConflictError otherwise. Names are kept up to date.
+func (table *routeTable) addRoute(route *RoutesField, replace bool) error {
+	// an existing static or dynamic pattern of the same shape ends at the same node
+	var existing *RoutesField
+	if leaf := table.treesFor(route.Host)[route.Method].find(route.segments); leaf != nil {
+		existing = sameMatchers(leaf.routes, route)
+	}
+	if existing != nil && !replace {
+		return &ConflictError{Method: route.Method, Host: route.Host, Pattern: route.Pattern,
+			Existing: existing.Pattern}
+	}
+
+	if existing != nil {
+		table.unname(existing)
+	}
+	table.setRoutes(route.Host, route.Method, route.segments, func(routes []*RoutesField) []*RoutesField {
+		return withRoute(routes, route)
+	})
+	if route.Name != "" {
+		table.names[route.Name] = route
+	}
+	return nil
+}
+
+// removeRoutes removes every route of the given host, method and pattern directories, whatever their matchers, and
+// reports whether there were any
+func (table *routeTable) removeRoutes(host string, method string, segments []string) bool {
+	leaf := table.treesFor(host)[method].find(segments)
+	if leaf == nil || len(leaf.routes) == 0 {
+		table.pruneHosts()
+		return false
+	}
+
+	for _, route := range leaf.routes {
+		table.unname(route)
+	}
+	table.setRoutes(host, method, segments, func(routes []*RoutesField) []*RoutesField {
+		return nil
+	})
+	return true
+}
+
+// setRoutes is a helper function for addRoute and removeRoutes that replaces the routes of the given host, method and
+// pattern directories by change(routes), as node.set does
+func (table *routeTable) setRoutes(host string, method string, segments []string,
+	change func(routes []*RoutesField) []*RoutesField) {
+	trees := table.treesFor(host)
+	if root := trees[method].set(segments, change); root != nil {
+		trees[method] = root
+	} else {
+		delete(trees, method)
+	}
+	table.pruneHosts()
+}
+
+// unname forgets the name of route, unless a later route has taken it
+func (table *routeTable) unname(route *RoutesField) {
+	if named, ok := table.names[route.Name]; ok && named == route {
+		delete(table.names, route.Name)
+	}
+}
+
+// match finds the highest precedence route of method matching the given request host labels and directories, and
+// whose matchers request meets, or nil. The routes of the host patterns matching the host are tried first, then the
+// routes without a host. If ignoreCase is set, static directories also match request directories differing only in
+// case. request may be nil, in which case only routes without matchers are found.
+func (table *routeTable) match(request *http.Request, hostLabels []string, method string, requestSegments []string,
+	ignoreCase bool) *RoutesField {
+	for _, host := range table.hosts {
+		if host.matches(hostLabels) {
+			if route := matchTrees(host.trees, request, method, requestSegments, ignoreCase); route != nil {
+				return route
+			}
+		}
+	}
+	return matchTrees(table.trees, request, method, requestSegments, ignoreCase)
+}
+
+// matchTrees is a helper function for match that finds the route in the trie of method among trees
+func matchTrees(trees map[string]*node, request *http.Request, method string, requestSegments []string,
+	ignoreCase bool) *RoutesField {
+	// the trie tries static directories before captures, so the first match found already has the highest precedence
+	if root, ok := trees[method]; ok {
+		return root.lookup(requestSegments, ignoreCase, request)
+	}
+	return nil
+}
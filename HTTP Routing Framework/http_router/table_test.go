@@ -0,0 +1,137 @@
+/******************************************************************************
+ *  table_test.go
+ *  Usage:    `go test -race`  or  `go test -race -v`
+ *  Description:
+ *    Tests for adding routes while the router serves requests. Run them with
+ *    the race detector to check that lookups never race with registration.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestConcurrentAddRoute adds routes from several goroutines while others serve requests, then checks every route
+// was added and that requests only ever saw a 200 for a route that existed or a 404 for one that did not yet.
+func TestConcurrentAddRoute(t *testing.T) {
+	const writers, routesPerWriter, readers = 4, 100, 4
+
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user", echoParams)
+
+	var wait sync.WaitGroup
+	done := make(chan struct{})
+	failures := make(chan string, readers)
+
+	for r := 0; r < readers; r++ {
+		wait.Add(1)
+		go func(r int) {
+			defer wait.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if body := serveBody(router, httpGet, "/users/cesar"); body != "user=cesar" {
+					failures <- "expected user=cesar and received " + body
+					return
+				}
+				path := fmt.Sprintf("/writer%d/route%d/%d", i%writers, i%routesPerWriter, r)
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, httptest.NewRequest(httpGet, "http://localhost:8080"+path, nil))
+				if status := recorder.Result().StatusCode; status != httpOK && status != httpNotFound {
+					failures <- fmt.Sprintf("unexpected status %d for %s", status, path)
+					return
+				}
+			}
+		}(r)
+	}
+
+	var writersWait sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		writersWait.Add(1)
+		go func(w int) {
+			defer writersWait.Done()
+			for i := 0; i < routesPerWriter; i++ {
+				router.AddRoute(httpGet, fmt.Sprintf("/writer%d/route%d/:reader", w, i), echoParams)
+			}
+			router.Use(tagMiddleware(""))
+			router.Group(fmt.Sprintf("/writer%d", w))
+		}(w)
+	}
+	writersWait.Wait()
+	close(done)
+	wait.Wait()
+	close(failures)
+
+	for failure := range failures {
+		t.Errorf("Test failed: %s", failure)
+	}
+	if count := len(router.Routes()); count != writers*routesPerWriter+1 {
+		t.Errorf("Test failed: Expected %d routes and found %d.", writers*routesPerWriter+1, count)
+	}
+	if body := serveBody(router, httpGet, "/writer3/route99/7"); body != "reader=7" {
+		t.Errorf("Test failed: Expected %s and received %s.", "reader=7", body)
+	}
+}
+
+// TestSnapshotConsistency checks that a table loaded before a change keeps serving the routes it had.
+func TestSnapshotConsistency(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/photos/:year/picture", echoParams)
+	before := router.loadTable()
+
+	router.AddRoute(httpGet, "/photos/:place/picture", echoParams)
+	router.AddRoute(httpGet, "/photos/:place/winter", echoParams)
+
+	route := before.match(nil, nil, httpGet, splitPattern("photos/2020/picture"), false)
+	if route == nil || route.Pattern != "photos/:year/picture" {
+		t.Errorf("Test failed: Expected the earlier table to keep photos/:year/picture and found %v.", route)
+	}
+	if route := before.match(nil, nil, httpGet, splitPattern("photos/paris/winter"), false); route != nil {
+		t.Errorf("Test failed: Expected the earlier table not to see photos/:place/winter.")
+	}
+	route = router.loadTable().match(nil, nil, httpGet, splitPattern("photos/paris/picture"), false)
+	if route == nil || route.Pattern != "photos/:place/picture" {
+		t.Errorf("Test failed: Expected the current table to serve photos/:place/picture and found %v.", route)
+	}
+}
+
+// TestChildrenManyNames checks that static children keep every name through additions and removals, whatever the
+// hashes of the names share.
+func TestChildrenManyNames(t *testing.T) {
+	const count = 5000
+
+	var static *children[*node]
+	leaves := make([]*node, count)
+	for i := range leaves {
+		leaves[i] = &node{}
+		static = static.with(fmt.Sprint("name", i), leaves[i])
+	}
+	for i := 0; i < count; i += 2 {
+		static = static.with(fmt.Sprint("name", i), nil)
+	}
+
+	for i, leaf := range leaves {
+		found := static.get(fmt.Sprint("name", i))
+		if i%2 == 0 && found != nil || i%2 == 1 && found != leaf {
+			t.Fatalf("Test failed: Unexpected node for name%d.", i)
+		}
+	}
+	for i := 1; i < count; i += 2 {
+		static = static.with(fmt.Sprint("name", i), nil)
+	}
+	if static != nil {
+		t.Errorf("Test failed: Expected an empty map after removing every name.")
+	}
+}
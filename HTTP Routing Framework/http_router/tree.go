@@ -0,0 +1,239 @@
+/*****************************************************************************
+ * tree.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// node is a single directory of a pattern in a method's routing trie. Static directories are looked up by name and
+// captures at the same depth share a capture child per constraint, since captures with the same constraint only
+// differ by their names. Likewise every trailing catch-all shares the one catch-all child, which always ends a pattern.
+//
+// Nodes are never modified once a route table holding them is published. Changes copy the nodes along the changed
+// pattern instead, sharing every other node with the previous trie.
+type node struct {
+	static     *children[*node]
+	folded     *children[*folding] // names of the static children by their lower case name
+	captures   []*node             // constrained captures in the order they were added, then the unconstrained capture
+	catchAll   *node
+	constraint string            // constraint of the capture this node stands for, e.g. `<int>`
+	match      func(string) bool // check compiled from constraint, nil if the capture matches any directory
+	routes     []*RoutesField    // routes ending here, the routes with the most matchers first
+}
+
+// folding lists the names of the static children of a node that fold to the same lower case name, in the order they
+// were added
+type folding struct {
+	names []string
+}
+
+// splitPattern splits a trimmed pattern or request path into its directories, the empty path has no directories
+func splitPattern(pattern string) []string {
+	if pattern == "" {
+		return nil
+	}
+	return strings.Split(pattern, "/")
+}
+
+// isCapture reports whether a pattern directory is a capture of the form `:variable_name`
+func isCapture(segment string) bool {
+	return strings.HasPrefix(segment, ":")
+}
+
+// isCatchAll reports whether a pattern directory is a catch-all of the form `*variable_name`
+func isCatchAll(segment string) bool {
+	return strings.HasPrefix(segment, "*")
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// find returns the node where the given pattern directories end, or nil if no pattern of that shape was added. Two
+// patterns end at the same node exactly when IsExistingPath considers them the same route.
+func (n *node) find(segments []string) *node {
+	current := n
+	for _, segment := range segments {
+		if current == nil {
+			return nil
+		}
+		switch {
+		case isCatchAll(segment):
+			current = current.catchAll
+		case isCapture(segment):
+			current = current.captureChild(captureConstraint(segment))
+		default:
+			current = current.static.get(segment)
+		}
+	}
+	return current
+}
+
+// captureChild returns the capture child for the given constraint, or nil if there is none
+func (n *node) captureChild(constraint string) *node {
+	for _, child := range n.captures {
+		if child.constraint == constraint {
+			return child
+		}
+	}
+	return nil
+}
+
+// set returns a copy of the trie rooted at n where the routes of the node the given pattern directories end at are
+// replaced by change(routes). change must not modify the routes it is given. Only the nodes along the pattern are
+// copied, nodes left without routes or children are dropped, and nil is returned if the whole trie is left empty. n
+// may be nil for an empty trie.
+func (n *node) set(segments []string, change func(routes []*RoutesField) []*RoutesField) *node {
+	copied := n.clone()
+	if len(segments) == 0 {
+		copied.routes = change(copied.routes)
+		return copied.orNil()
+	}
+
+	segment, rest := segments[0], segments[1:]
+	switch {
+	case isCatchAll(segment):
+		if len(rest) > 0 {
+			panic("http_router: catch-all " + segment + " must be the last directory of its pattern")
+		}
+		copied.catchAll = copied.catchAll.set(rest, change)
+	case isCapture(segment):
+		copied.setCapture(captureConstraint(segment), rest, change)
+	default:
+		existing := copied.static.get(segment)
+		child := existing.set(rest, change)
+		copied.static = copied.static.with(segment, child)
+		copied.setFolded(segment, existing, child)
+	}
+	return copied.orNil()
+}
+
+// setFolded is a helper function for set that updates the folded index after the static child called segment was
+// replaced by child, or removed if child is nil. Only adding or removing a child changes the index, as it holds names.
+func (n *node) setFolded(segment string, existing *node, child *node) {
+	if (existing == nil) == (child == nil) {
+		return
+	}
+	key := strings.ToLower(segment)
+	var names []string
+	if fold := n.folded.get(key); fold != nil {
+		names = fold.names
+	}
+	if child != nil {
+		names = append(slices.Clip(names), segment)
+	} else {
+		names = slices.DeleteFunc(slices.Clone(names), func(name string) bool { return name == segment })
+	}
+
+	if len(names) == 0 {
+		n.folded = n.folded.with(key, nil)
+	} else {
+		n.folded = n.folded.with(key, &folding{names: names})
+	}
+}
+
+// setCapture is a helper function for set that replaces the capture child for the given constraint. Constrained
+// captures are kept ahead of the unconstrained capture so that they are tried first.
+func (n *node) setCapture(constraint string, rest []string, change func(routes []*RoutesField) []*RoutesField) {
+	for i, child := range n.captures {
+		if child.constraint != constraint {
+			continue
+		}
+		if child = child.set(rest, change); child == nil {
+			n.captures = append(n.captures[:i], n.captures[i+1:]...)
+		} else {
+			n.captures[i] = child
+		}
+		return
+	}
+
+	child := (&node{constraint: constraint, match: compileConstraint(constraint)}).set(rest, change)
+	if child == nil {
+		return
+	}
+	last := len(n.captures) - 1
+	if constraint != "" && last >= 0 && n.captures[last].constraint == "" {
+		n.captures = append(n.captures[:last], child, n.captures[last])
+	} else {
+		n.captures = append(n.captures, child)
+	}
+}
+
+// clone returns a copy of n that can be changed without changing n, or a new node if n is nil
+func (n *node) clone() *node {
+	if n == nil {
+		return &node{}
+	}
+	copied := *n
+	copied.captures = slices.Clone(n.captures)
+	return &copied
+}
+
+// orNil returns n, or nil if n holds no route and has no children
+func (n *node) orNil() *node {
+	if len(n.routes) == 0 && n.static == nil && len(n.captures) == 0 && n.catchAll == nil {
+		return nil
+	}
+	return n
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// lookup finds the highest precedence route matching the given request directories, or nil. Static children are
+// tried before the capture children, then the catch-all child, and the search backtracks on failure, so the first
+// route found is the one with the most non-capturing directories before each of its captures, as IsHigherPrecedence
+// orders them. A directory failing a capture's constraint falls through to the next candidate, and so does a node
+// none of whose routes has its matchers met by request. If ignoreCase is set, the static children whose names only
+// differ in case are tried after the one of the same name, in the order they were added.
+func (n *node) lookup(segments []string, ignoreCase bool, request *http.Request) *RoutesField {
+	if len(segments) == 0 {
+		return n.matchRoute(request)
+	}
+	child := n.static.get(segments[0])
+	if child != nil {
+		if found := child.lookup(segments[1:], ignoreCase, request); found != nil {
+			return found
+		}
+	}
+	if ignoreCase && n.folded != nil {
+		if fold := n.folded.get(strings.ToLower(segments[0])); fold != nil {
+			for _, name := range fold.names {
+				if name == segments[0] {
+					continue
+				}
+				if found := n.static.get(name).lookup(segments[1:], ignoreCase, request); found != nil {
+					return found
+				}
+			}
+		}
+	}
+	for _, child := range n.captures {
+		if child.match != nil && !child.match(segments[0]) {
+			continue
+		}
+		if found := child.lookup(segments[1:], ignoreCase, request); found != nil {
+			return found
+		}
+	}
+	// a catch-all matches all of the one or more remaining directories
+	if n.catchAll != nil {
+		return n.catchAll.matchRoute(request)
+	}
+	return nil
+}
+
+// matchRoute returns the first route of n whose matchers request meets, which is the one with the most matchers, or
+// nil if there is none
+func (n *node) matchRoute(request *http.Request) *RoutesField {
+	for _, route := range n.routes {
+		if route.matches(request) {
+			return route
+		}
+	}
+	return nil
+}
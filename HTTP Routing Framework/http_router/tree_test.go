@@ -0,0 +1,179 @@
+/******************************************************************************
+ *  tree_test.go
+ *  Usage:    `go test`  or  `go test -bench .`
+ *  Description:
+ *    Tests and benchmarks for the per-method routing trie used by ServeHTTP.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// serveBody sends a single request for path to router and returns the response body
+func serveBody(router *HTTPRouter, method string, path string) string {
+	request := httptest.NewRequest(method, "http://localhost:8080"+path, nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return responseBodyToString(recorder.Result())
+}
+
+// discardResponseWriter is a response writer that discards the response, so benchmarks only measure the router
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (response discardResponseWriter) Header() http.Header            { return response.header }
+func (response discardResponseWriter) Write(body []byte) (int, error) { return len(body), nil }
+func (response discardResponseWriter) WriteHeader(status int)         {}
+
+// newBenchmarkRouter creates a router with count dynamic routes of the form /resource<i>/items/:item/detail
+func newBenchmarkRouter(count int) *HTTPRouter {
+	router := NewRouter()
+	for i := 0; i < count; i++ {
+		router.AddRoute(httpGet, fmt.Sprintf("/resource%d/items/:item/detail", i), echoPathHandler)
+	}
+	return router
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestTreePrecedence registers overlapping routes in an order where the highest precedence route is added neither
+// first nor last, and checks each request is served by the route IsHigherPrecedence would pick.
+func TestTreePrecedence(t *testing.T) {
+	patterns := []string{
+		"/path/:dir/:file",
+		"/path/to/:file",
+		"/:root/to/file",
+		"/path/to/file",
+		"/path/:dir/file",
+	}
+
+	router := NewRouter()
+	for _, pattern := range patterns {
+		pattern := pattern
+		router.AddRoute(httpGet, pattern, func(response http.ResponseWriter, request *http.Request) {
+			response.Write([]byte(pattern))
+		})
+	}
+
+	tests := map[string]string{
+		"/path/to/file":   "/path/to/file",
+		"/path/to/other":  "/path/to/:file",
+		"/path/from/file": "/path/:dir/file",
+		"/path/from/x":    "/path/:dir/:file",
+		"/root/to/file":   "/:root/to/file",
+	}
+	for path, expected := range tests {
+		if body := serveBody(router, httpGet, path); body != expected {
+			t.Errorf("Test failed: Expected %s to be served by %s, served by %s.", path, expected, body)
+		}
+	}
+
+	if body := serveBody(router, httpGet, "/root/to/other"); body != "404 page not found\n" {
+		t.Errorf("Test failed: Expected a 404 and received %s.", body)
+	}
+}
+
+// TestTreeOverwrite checks that re-adding a route of the same shape replaces the capture names and handler instead
+// of creating a second route, while routes that only share a prefix coexist.
+func TestTreeOverwrite(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/photos/:year/picture", echoPathHandler)
+	router.AddRoute(httpGet, "/photos/:year/july", echoPathHandler)
+	router.AddRoute(httpGet, "photos/:place/picture/", echoParams)
+
+	if len(router.Routes()) != 2 {
+		t.Fatalf("Test failed: Expected 2 routes and found %d.", len(router.Routes()))
+	}
+	if body := serveBody(router, httpGet, "/photos/paris/picture"); body != "place=paris" {
+		t.Errorf("Test failed: Expected place=paris and received %s.", body)
+	}
+	if body := serveBody(router, httpGet, "/photos/2020/july"); body != "/photos/2020/july" {
+		t.Errorf("Test failed: Expected /photos/2020/july and received %s.", body)
+	}
+}
+
+// TestTreeCatchAll checks that a trailing catch-all captures one or more remaining directories and only serves
+// requests that no static or capturing route matches.
+func TestTreeCatchAll(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/static/*file", echoParams)
+	router.AddRoute(httpGet, "/static/:dir/index.html", echoPathHandler)
+	router.AddRoute(httpGet, "/static/favicon.ico", echoMethodHandler)
+
+	tests := map[string]string{
+		"/static/css/site/main.css": "file=css%2Fsite%2Fmain.css",
+		"/static/main.js":           "file=main.js",
+		"/static/docs/index.html":   "/static/docs/index.html",
+		"/static/favicon.ico":       "GET",
+		"/static":                   "404 page not found\n",
+	}
+	for path, expected := range tests {
+		if body := serveBody(router, httpGet, path); body != expected {
+			t.Errorf("Test failed: Expected %q for %s and received %q.", expected, path, body)
+		}
+	}
+}
+
+// TestCatchAllPrecedence checks that IsHigherPrecedence ranks catch-alls below captures and static directories.
+func TestCatchAllPrecedence(t *testing.T) {
+	if !IsHigherPrecedence("static/:dir/:file", "static/*file") {
+		t.Errorf("Test failed: Expected a capture to take precedence over a catch-all.")
+	}
+	if IsHigherPrecedence("static/*file", "static/css/:file") {
+		t.Errorf("Test failed: Expected a static directory to take precedence over a catch-all.")
+	}
+	if !IsSameDynamicPattern("static/css/main.css", "static/*file") || IsSameDynamicPattern("static", "static/*file") {
+		t.Errorf("Test failed: Expected a catch-all to match one or more remaining directories.")
+	}
+}
+
+/******************************************************************************/
+/*                               Benchmarks                                   */
+/******************************************************************************/
+
+// benchmarkLookup measures finding the route for a request to the last route added to a router holding count routes
+func benchmarkLookup(b *testing.B, count int) {
+	router := newBenchmarkRouter(count)
+	table := router.loadTable()
+	requestSegments := splitPattern(fmt.Sprintf("resource%d/items/42/detail", count-1))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if table.match(nil, nil, httpGet, requestSegments, false) == nil {
+			b.Fatal("no route found")
+		}
+	}
+}
+
+// benchmarkServeHTTP measures serving a request to the last route added to a router holding count routes
+func benchmarkServeHTTP(b *testing.B, count int) {
+	router := newBenchmarkRouter(count)
+	request := httptest.NewRequest(httpGet, fmt.Sprintf("http://localhost:8080/resource%d/items/42/detail", count-1), nil)
+	response := discardResponseWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		router.ServeHTTP(response, request)
+	}
+}
+
+func BenchmarkLookup10(b *testing.B)       { benchmarkLookup(b, 10) }
+func BenchmarkLookup100(b *testing.B)      { benchmarkLookup(b, 100) }
+func BenchmarkLookup1000(b *testing.B)     { benchmarkLookup(b, 1000) }
+func BenchmarkLookup10000(b *testing.B)    { benchmarkLookup(b, 10000) }
+func BenchmarkServeHTTP10(b *testing.B)    { benchmarkServeHTTP(b, 10) }
+func BenchmarkServeHTTP10000(b *testing.B) { benchmarkServeHTTP(b, 10000) }
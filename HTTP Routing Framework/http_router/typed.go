@@ -0,0 +1,60 @@
+/*****************************************************************************
+ * typed.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+)
+
+// statusCoder is implemented by outputs of typed handlers that are answered with a status other than 200
+type statusCoder interface {
+	StatusCode() int
+}
+
+// Typed adapts a handler taking its input as a struct and returning its output into an ErrorFunc that can be added
+// with AddErrorRoute, e.g.
+//
+//	func createUser(ctx context.Context, in CreateUserReq) (CreateUserResp, error) { ... }
+//
+//	router.AddErrorRoute("POST", "/users/:team", Typed(createUser))
+//
+// The input is bound from the request as Context.Bind binds it, from its JSON body and the fields tagged `path`,
+// `query` and `header`, and validated against its validate tags, so the handler, which is given the request context,
+// only sees valid input. Its output is written as JSON with a 200, or with the status its StatusCode method returns if
+// it has one. The returned ErrorFunc fails with the error of a request that can't be bound or is invalid, or of the
+// handler, for the router to answer. In must be a struct whose validate tags only name registered rules, and give the
+// built-in rules valid parameters and fields they can check. Other inputs panic when the handler is created, rather
+// than on every request.
+func Typed[In any, Out any](handler func(ctx context.Context, in In) (Out, error)) ErrorFunc {
+	inputType := reflect.TypeOf((*In)(nil)).Elem()
+	if inputType.Kind() != reflect.Struct {
+		panic("http_router: typed handler input " + inputType.String() + " must be a struct")
+	}
+	if err := DefaultValidator.checkType(inputType); err != nil {
+		panic(err)
+	}
+
+	return func(response http.ResponseWriter, request *http.Request) error {
+		ctx := NewContext(response, request)
+		var in In
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+
+		out, err := handler(request.Context(), in)
+		if err != nil {
+			return err
+		}
+		status := http.StatusOK
+		if coder, ok := any(out).(statusCoder); ok {
+			status = coder.StatusCode()
+		}
+		return ctx.JSON(status, out)
+	}
+}
@@ -0,0 +1,127 @@
+/******************************************************************************
+ *  typed_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for typed handlers binding their input and encoding their output.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// createUserReq is the input of createUser, bound from every part of the request
+type createUserReq struct {
+	Team   string   `path:"team"`
+	DryRun bool     `query:"dry_run"`
+	Token  string   `header:"X-Token"`
+	Langs  []string `header:"Accept-Language"`
+	Name   string   `json:"name"`
+	Age    int      `json:"age"`
+}
+
+// createUserResp is the output of createUser, answered with a 201
+type createUserResp struct {
+	ID     string `json:"id"`
+	DryRun bool   `json:"dry_run"`
+}
+
+func (createUserResp) StatusCode() int { return http.StatusCreated }
+
+// errNoTeam is the error createUser fails with for the team "none"
+var errNoTeam = NewStatusError(http.StatusNotFound, "no such team")
+
+// createUser is a typed handler echoing its input, which fails for the team "none" and for the user "boom"
+func createUser(ctx context.Context, in createUserReq) (createUserResp, error) {
+	switch {
+	case in.Team == "none":
+		return createUserResp{}, errNoTeam
+	case in.Name == "boom":
+		return createUserResp{}, errors.New("database password is hunter2")
+	}
+	id := in.Team + "/" + in.Name + "/" + in.Token + "/" + strings.Join(in.Langs, ",")
+	return createUserResp{ID: id, DryRun: in.DryRun}, nil
+}
+
+// serveTyped sends a POST request with the given body and content type to a router holding createUser
+func serveTyped(path string, body string, contentType string) *httptest.ResponseRecorder {
+	router := NewRouter()
+	router.AddErrorRoute(httpPost, "/teams/:team/users", Typed(createUser))
+
+	request := httptest.NewRequest(httpPost, "http://localhost:8080"+path, strings.NewReader(body))
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("X-Token", "secret")
+	request.Header.Add("Accept-Language", "en")
+	request.Header.Add("Accept-Language", "fr")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestTypedHandler checks that the input is bound from the path, query, headers and JSON body, and the output is
+// written as JSON with the status it gives.
+func TestTypedHandler(t *testing.T) {
+	recorder := serveTyped("/teams/core/users?dry_run=true", `{"name":"bob","age":7}`, "application/json")
+
+	expected := "{\"id\":\"core/bob/secret/en,fr\",\"dry_run\":true}\n"
+	if recorder.Code != http.StatusCreated || recorder.Body.String() != expected {
+		t.Errorf("Test failed: Expected 201 %s and received %d %s.", expected, recorder.Code, recorder.Body)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json; charset=utf-8" {
+		t.Errorf("Test failed: Expected a JSON response and received %s.", contentType)
+	}
+
+	// a request without a body only binds the tagged fields
+	recorder = serveTyped("/teams/core/users", "", "")
+	if expected := "{\"id\":\"core//secret/en,fr\",\"dry_run\":false}\n"; recorder.Body.String() != expected {
+		t.Errorf("Test failed: Expected %s and received %s.", expected, recorder.Body)
+	}
+}
+
+// TestTypedHandlerErrors checks that requests that can't be bound and handler errors are answered with the status
+// ErrorStatus maps them to, without showing the message of server errors.
+func TestTypedHandlerErrors(t *testing.T) {
+	captureLog(t)
+	tests := []struct {
+		path        string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{"/teams/core/users?dry_run=maybe", "", "", "400 http_router: invalid value for dry_run: "},
+		{"/teams/core/users", `{"name":`, "application/json", "400 http_router: invalid request body: "},
+		{"/teams/core/users", "name=bob", "application/x-www-form-urlencoded", "415 unsupported content type "},
+		{"/teams/none/users", "", "", "404 no such team"},
+		{"/teams/core/users", `{"name":"boom"}`, "application/problem+json", "500 internal server error"},
+	}
+	for _, test := range tests {
+		recorder := serveTyped(test.path, test.body, test.contentType)
+		if body := recorder.Body.String(); !strings.HasPrefix(body, test.expected) {
+			t.Errorf("Test failed: Expected %q for %s and received %q.", test.expected, test.path, body)
+		}
+	}
+}
+
+// TestTypedHandlerInput checks that a typed handler whose input isn't a struct panics when it is created.
+func TestTypedHandlerInput(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Test failed: Expected a panic for a string input.")
+		}
+	}()
+	Typed(func(ctx context.Context, in string) (string, error) { return in, nil })
+}
@@ -0,0 +1,93 @@
+/*****************************************************************************
+ * url.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WithName names the route being added, so that URLs to it can be built with HTTPRouter.URL. A name given to a later
+// route is taken from the earlier one.
+func WithName(name string) RouteOption {
+	return func(route *RoutesField) {
+		route.Name = name
+	}
+}
+
+// URL builds the path of the route called name, filling its captures with params given as name, value pairs, e.g.
+//
+//	URL("recent", "user", "cesar")
+//
+// gives "/users/cesar/recent" for a route added with AddRoute("GET", "/users/:user/recent", ..., WithName("recent")).
+// A capture used several times takes its values in the order given. Values are percent-encoded, except for the '/'
+// separating the directories of a catch-all, and the URL of a route added with a trailing '/' keeps it. An error is
+// returned if the route does not exist, if a capture is missing a value or given an empty one, if a value fails the
+// capture's constraint, or if params are left over.
+func (router *HTTPRouter) URL(name string, params ...string) (string, error) {
+	route, ok := router.loadTable().names[name]
+	if !ok {
+		return "", fmt.Errorf("http_router: no route named %q", name)
+	}
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("http_router: odd number of params for route %q", name)
+	}
+
+	values := url.Values{}
+	for i := 0; i < len(params); i += 2 {
+		values.Add(params[i], params[i+1])
+	}
+
+	segments := splitPattern(route.Pattern)
+	path := make([]string, len(segments))
+	for i, segment := range segments {
+		if !isCapture(segment) && !isCatchAll(segment) {
+			path[i] = segment
+			continue
+		}
+
+		capture := captureName(segment)
+		if len(values[capture]) == 0 {
+			return "", fmt.Errorf("http_router: missing value for %s in route %q", segment, name)
+		}
+		value := values[capture][0]
+		values[capture] = values[capture][1:]
+		if strings.Trim(value, "/") == "" {
+			return "", fmt.Errorf("http_router: empty value for %s in route %q", segment, name)
+		}
+
+		if isCatchAll(segment) {
+			path[i] = escapeCatchAll(value)
+			continue
+		}
+		if !matchesConstraint(segment, value) {
+			return "", fmt.Errorf("http_router: value %q does not match %s in route %q", value, segment, name)
+		}
+		path[i] = url.PathEscape(value)
+	}
+
+	for capture, remaining := range values {
+		if len(remaining) > 0 {
+			return "", fmt.Errorf("http_router: extra value for %s in route %q", capture, name)
+		}
+	}
+	built := "/" + strings.Join(path, "/")
+	if route.trailingSlash {
+		built += "/"
+	}
+	return built, nil
+}
+
+// escapeCatchAll percent-encodes each directory of a catch-all value, keeping the '/' between them
+func escapeCatchAll(value string) string {
+	directories := strings.Split(strings.Trim(value, "/"), "/")
+	for i := range directories {
+		directories[i] = url.PathEscape(directories[i])
+	}
+	return strings.Join(directories, "/")
+}
@@ -0,0 +1,81 @@
+/******************************************************************************
+ *  url_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for building URLs to named routes.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"testing"
+)
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestURL checks that URLs to named routes fill in repeated captures in order and percent-encode their values.
+func TestURL(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:user/recent", echoParams, WithName("recent"))
+	router.AddRoute(httpGet, "/path/to/:file/:file", echoParams, WithName("files"))
+	router.AddRoute(httpGet, "/static/*file", echoParams, WithName("static"))
+	router.AddRoute(httpGet, "/", echoPathHandler, WithName("index"))
+	router.AddRoute(httpGet, "/dirs/:dir/", echoParams, WithName("dirs"))
+
+	tests := []struct {
+		name     string
+		params   []string
+		expected string
+	}{
+		{"recent", []string{"user", "cesar"}, "/users/cesar/recent"},
+		{"recent", []string{"user", "a b/c"}, "/users/a%20b%2Fc/recent"},
+		{"files", []string{"file", "a", "file", "b"}, "/path/to/a/b"},
+		{"static", []string{"file", "css/main file.css"}, "/static/css/main%20file.css"},
+		{"index", nil, "/"},
+		{"dirs", []string{"dir", "docs"}, "/dirs/docs/"},
+	}
+	for _, test := range tests {
+		path, err := router.URL(test.name, test.params...)
+		if err != nil || path != test.expected {
+			t.Errorf("Test failed: Expected %s for %s and received %s, %v.", test.expected, test.name, path, err)
+		}
+	}
+
+	// the URL must route back to the same captures
+	path, _ := router.URL("files", "file", "b", "file", "a")
+	if body := serveBody(router, httpGet, path); body != "file=b&file=a" {
+		t.Errorf("Test failed: Expected %s and received %s.", "file=b&file=a", body)
+	}
+	path, _ = router.URL("dirs", "dir", "docs")
+	if body := serveBody(router, httpGet, path); body != "dir=docs" {
+		t.Errorf("Test failed: Expected %s and received %s.", "dir=docs", body)
+	}
+}
+
+// TestURLErrors checks that unknown routes and missing, extra or invalid params are reported.
+func TestURLErrors(t *testing.T) {
+	router := NewRouter()
+	router.AddRoute(httpGet, "/users/:id<int>", echoParams, WithName("user"))
+	router.AddRoute(httpGet, "/users/:name", echoParams, WithName("old"))
+	router.AddRoute(httpGet, "/users/:user", echoParams, WithName("profile"))
+
+	failing := [][]string{
+		{"missing"},
+		{"user"},
+		{"user", "id"},
+		{"user", "id", "abc"},
+		{"user", "id", "1", "id", "2"},
+		{"user", "id", "1", "page", "2"},
+		{"old", "name", "cesar"},
+	}
+	for _, params := range failing {
+		if path, err := router.URL(params[0], params[1:]...); err == nil {
+			t.Errorf("Test failed: Expected an error for %v and received %s.", params, path)
+		}
+	}
+	if path, err := router.URL("profile", "user", "cesar"); err != nil || path != "/users/cesar" {
+		t.Errorf("Test failed: Expected %s and received %s, %v.", "/users/cesar", path, err)
+	}
+}
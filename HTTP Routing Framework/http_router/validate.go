@@ -0,0 +1,402 @@
+/*****************************************************************************
+ * validate.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Rule checks a field against the parameter its validate tag gives the rule, e.g. "64" for `max=64` or "" for
+// `email`, and returns an error describing how the field fails it, such as "must be an email address". Rules are
+// given the value a pointer field points to rather than the pointer.
+type Rule func(value reflect.Value, param string) error
+
+// FieldError describes a field failing a rule
+type FieldError struct {
+	Field   string `json:"field"`   // name the field is bound under, e.g. "address.city" or "items[2].name"
+	Rule    string `json:"rule"`    // name of the failed rule, e.g. "max"
+	Message string `json:"message"` // what the failed rule requires, e.g. "must be at most 64 characters"
+}
+
+// ValidationError lists every field of a value that failed validation. It should be answered with a 422.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error lists the failing fields with how each fails
+func (err *ValidationError) Error() string {
+	messages := make([]string, len(err.Fields))
+	for i, field := range err.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return "http_router: invalid fields: " + strings.Join(messages, "; ")
+}
+
+// Validator checks the fields of structs against the rules in their validate tags, e.g.
+//
+//	Name  string `json:"name" validate:"required,max=64"`
+//	Email string `json:"email" validate:"omitempty,email"`
+//	Role  string `json:"role" validate:"oneof=admin member"`
+//
+// Rules are separated by commas and run in order. Beyond the built-in rules required, min, max, email and oneof,
+// rules can be added with RegisterRule. Fields that are nil pointers only fail required, and fields given omitempty
+// skip their rules when they are zero.
+type Validator struct {
+	mutex   sync.RWMutex
+	rules   map[string]Rule
+	checks  map[string]ruleCheck // checks of the built-in rules that only apply to some fields
+	checked *sync.Map            // struct types whose tags were checked, mapped to the mistake found in them, if any
+}
+
+// ruleCheck returns an error if a rule can't check fields of type fieldType with the parameter param
+type ruleCheck func(fieldType reflect.Type, param string) error
+
+// DefaultValidator is the validator Validate uses, which checks every value bound by Context and typed handlers
+var DefaultValidator = NewValidator()
+
+// NewValidator creates a validator with the built-in rules
+func NewValidator() *Validator {
+	return &Validator{rules: map[string]Rule{
+		"required": requiredRule,
+		"min":      minRule,
+		"max":      maxRule,
+		"email":    emailRule,
+		"oneof":    oneOfRule,
+	}, checks: map[string]ruleCheck{
+		"min":   checkBound,
+		"max":   checkBound,
+		"email": checkEmail,
+	}, checked: new(sync.Map)}
+}
+
+// RegisterRule adds a rule to the default validator, replacing any rule of the same name
+func RegisterRule(name string, rule Rule) {
+	DefaultValidator.RegisterRule(name, rule)
+}
+
+// Validate checks value with the default validator
+func Validate(value any) error {
+	return DefaultValidator.Validate(value)
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// RegisterRule adds a rule to the validator, replacing any rule of the same name. Rules should be registered before
+// the typed handlers whose input uses them are created, as Typed checks the rules of their input exist.
+func (validator *Validator) RegisterRule(name string, rule Rule) {
+	validator.mutex.Lock()
+	defer validator.mutex.Unlock()
+	validator.rules[name] = rule
+	delete(validator.checks, name)
+	// types whose tags named the rule are no longer mistaken
+	validator.checked = new(sync.Map)
+}
+
+// checkType returns an error describing the first mistake in the validate tags of the fields of a struct type, or of
+// the structs it holds: a rule the validator doesn't know, or a built-in rule given an invalid parameter or a field of
+// a type it can't check.
+func (validator *Validator) checkType(structType reflect.Type) error {
+	validator.mutex.RLock()
+	defer validator.mutex.RUnlock()
+	return validator.checkedType(structType)
+}
+
+// checkedType is a helper function for checkType and Validate that checks the tags of a struct type as checkType
+// does, only checking each type once. The validator must be locked.
+func (validator *Validator) checkedType(structType reflect.Type) error {
+	if mistake, ok := validator.checked.Load(structType); ok {
+		err, _ := mistake.(error)
+		return err
+	}
+	err := validator.checkStruct(structType, "", map[reflect.Type]bool{})
+	validator.checked.Store(structType, err)
+	return err
+}
+
+// checkStruct is a helper function for checkType that checks the fields of a struct type, naming them after prefix.
+// Types already in seen are skipped, so that recursive types end.
+func (validator *Validator) checkStruct(structType reflect.Type, prefix string, seen map[reflect.Type]bool) error {
+	if structType.Kind() != reflect.Struct || seen[structType] {
+		return nil
+	}
+	seen[structType] = true
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		// the fields of embedded structs are bound even if the struct's type is unexported
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := validator.checkStruct(field.Type, prefix, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		fieldType := elemType(field.Type)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := validator.checkField(fieldType, name, tag); err != nil {
+				return err
+			}
+		}
+		if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+			fieldType, name = elemType(fieldType.Elem()), name+"[]"
+		}
+		if err := validator.checkStruct(fieldType, name+".", seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkField is a helper function for checkStruct that checks the rules of the tag of a field of type fieldType
+func (validator *Validator) checkField(fieldType reflect.Type, name string, tag string) error {
+	for _, ruleText := range strings.Split(tag, ",") {
+		ruleName, param, _ := strings.Cut(ruleText, "=")
+		if ruleName == "omitempty" {
+			continue
+		}
+		if _, ok := validator.rules[ruleName]; !ok {
+			return fmt.Errorf("http_router: unknown validation rule %s for %s", ruleName, name)
+		}
+		if check := validator.checks[ruleName]; check != nil {
+			if err := check(fieldType, param); err != nil {
+				return fmt.Errorf("http_router: invalid validation rule %s for %s: %w", ruleText, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// elemType returns the type a pointer type points to, through any number of pointers, or fieldType itself
+func elemType(fieldType reflect.Type) reflect.Type {
+	for fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType
+}
+
+// Validate checks the fields of a struct, or of the struct a pointer points to, against the rules in their validate
+// tags, returning a ValidationError listing every failing field. Fields of nested structs, and of structs in slices,
+// are checked too. Values that aren't structs have no fields and are always valid. A mistake in the tags of the
+// struct, as checkType finds them, is returned as its own error rather than a ValidationError, so that it is answered
+// as a server error. The tags of each struct type are only checked once.
+func (validator *Validator) Validate(value any) error {
+	validator.mutex.RLock()
+	defer validator.mutex.RUnlock()
+
+	target := reflect.Indirect(reflect.ValueOf(value))
+	if target.Kind() != reflect.Struct {
+		return nil
+	}
+	if err := validator.checkedType(target.Type()); err != nil {
+		return err
+	}
+	var failed []FieldError
+	validator.validateStruct(target, "", &failed)
+	if len(failed) > 0 {
+		return &ValidationError{Fields: failed}
+	}
+	return nil
+}
+
+// validateStruct is a helper function for Validate that checks the fields of a struct value, naming them after
+// prefix, and appends those failing to failed
+func (validator *Validator) validateStruct(value reflect.Value, prefix string, failed *[]FieldError) {
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		// the fields of embedded structs are bound even if the struct's type is unexported
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			validator.validateStruct(value.Field(i), prefix, failed)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(field)
+		fieldValue := value.Field(i)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if !validator.validateField(fieldValue, name, tag, failed) {
+				continue
+			}
+		}
+
+		fieldValue = reflect.Indirect(fieldValue)
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			validator.validateStruct(fieldValue, name+".", failed)
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fieldValue.Len(); j++ {
+				validator.validateStruct(reflect.Indirect(fieldValue.Index(j)), fmt.Sprintf("%s[%d].", name, j), failed)
+			}
+		}
+	}
+}
+
+// validateField is a helper function for validateStruct that checks a field against the rules of its tag, appending
+// the first rule it fails to failed, and reports whether it passed
+func (validator *Validator) validateField(value reflect.Value, name string, tag string, failed *[]FieldError) bool {
+	for _, ruleText := range strings.Split(tag, ",") {
+		ruleName, param, _ := strings.Cut(ruleText, "=")
+		if ruleName == "omitempty" {
+			if value.IsZero() {
+				return true
+			}
+			continue
+		}
+		rule, ok := validator.rules[ruleName]
+		if !ok {
+			panic("http_router: unknown validation rule " + ruleName + " for " + name)
+		}
+
+		// nil pointers have no value to check and only fail required
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Pointer && ruleName != "required" {
+			return true
+		}
+
+		if err := rule(value, param); err != nil {
+			*failed = append(*failed, FieldError{Field: name, Rule: ruleName, Message: err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+// fieldName returns the name a field is bound under, its json, form, query, path or header tag, or else its name
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "query", "path", "header"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// requiredRule fails nil pointers, zero values, and empty strings, slices and maps
+func requiredRule(value reflect.Value, param string) error {
+	if value.IsZero() || (hasLength(value) && value.Len() == 0) {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// minRule fails numbers below param, and strings, slices and maps shorter than param
+func minRule(value reflect.Value, param string) error {
+	return boundRule(value, param, "at least", func(size float64, bound float64) bool { return size >= bound })
+}
+
+// maxRule fails numbers above param, and strings, slices and maps longer than param
+func maxRule(value reflect.Value, param string) error {
+	return boundRule(value, param, "at most", func(size float64, bound float64) bool { return size <= bound })
+}
+
+// boundRule is a helper function for minRule and maxRule that compares the size of a value to the bound param gives,
+// where the size of a string is its number of characters and that of a slice or map its number of items
+func boundRule(value reflect.Value, param string, describe string,
+	within func(size float64, bound float64) bool) error {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("http_router: invalid validation bound " + param)
+	}
+
+	var size float64
+	switch value.Kind() {
+	case reflect.String:
+		if size = float64(utf8.RuneCountInString(value.String())); !within(size, bound) {
+			return fmt.Errorf("must be %s %s characters", describe, param)
+		}
+		return nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if size = float64(value.Len()); !within(size, bound) {
+			return fmt.Errorf("must have %s %s items", describe, param)
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	default:
+		panic("http_router: can't bound a field of type " + value.Type().String())
+	}
+	if !within(size, bound) {
+		return fmt.Errorf("must be %s %s", describe, param)
+	}
+	return nil
+}
+
+// checkBound is the check of minRule and maxRule, which need a numeric bound and a number, string, slice or map
+func checkBound(fieldType reflect.Type, param string) error {
+	if _, err := strconv.ParseFloat(param, 64); err != nil {
+		return fmt.Errorf("invalid bound %q", param)
+	}
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return nil
+	}
+	return fmt.Errorf("can't bound a field of type %s", fieldType)
+}
+
+// checkEmail is the check of emailRule, which needs a string
+func checkEmail(fieldType reflect.Type, param string) error {
+	if fieldType.Kind() != reflect.String {
+		return fmt.Errorf("can't check a field of type %s", fieldType)
+	}
+	return nil
+}
+
+// emailRule fails strings that aren't a bare email address such as "bob@example.com"
+func emailRule(value reflect.Value, param string) error {
+	address, err := mail.ParseAddress(value.String())
+	if err != nil || address.Address != value.String() {
+		return errors.New("must be an email address")
+	}
+	return nil
+}
+
+// oneOfRule fails values whose text isn't one of the space separated words of param
+func oneOfRule(value reflect.Value, param string) error {
+	text := fmt.Sprint(value.Interface())
+	options := strings.Fields(param)
+	for _, option := range options {
+		if text == option {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(options, ", "))
+}
+
+// hasLength reports whether value is of a kind with a length
+func hasLength(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+/******************************************************************************
+ *  validate_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for validating bound values against the rules of their tags.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// address is nested in signup to check the names of nested fields
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+// signup has a field for every built-in rule
+type signup struct {
+	Name      string    `json:"name" validate:"required,min=1,max=8"`
+	Email     string    `json:"email" validate:"omitempty,email"`
+	Role      string    `json:"role" validate:"oneof=admin member"`
+	Age       *int      `json:"age" validate:"min=18"`
+	Tags      []string  `json:"tags" validate:"max=2"`
+	Home      address   `json:"home"`
+	Addresses []address `json:"addresses"`
+}
+
+// failedFields returns the names and rules of the fields err lists, e.g. "name:required"
+func failedFields(err error) []string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	fields := make([]string, len(validationErr.Fields))
+	for i, field := range validationErr.Fields {
+		fields[i] = field.Field + ":" + field.Rule
+	}
+	return fields
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestValidate checks that every failing field is listed under the name it is bound by, including nested fields and
+// fields of structs in slices, while omitted optional fields and nil pointers pass.
+func TestValidate(t *testing.T) {
+	young := 12
+	tests := []struct {
+		value    signup
+		expected []string
+	}{
+		{
+			signup{Name: "bob", Role: "admin", Home: address{City: "Paris"}},
+			nil,
+		},
+		{
+			signup{Name: "bob", Email: "bob@example.com", Role: "member", Tags: []string{"a"},
+				Home: address{City: "Paris"}},
+			nil,
+		},
+		{
+			signup{Name: "robert the third", Email: "Bob <bob@example.com>", Role: "owner", Age: &young,
+				Tags: []string{"a", "b", "c"}, Addresses: []address{{City: "Rome"}, {}}},
+			[]string{"name:max", "email:email", "role:oneof", "age:min", "tags:max", "home.city:required",
+				"addresses[1].city:required"},
+		},
+	}
+	for _, test := range tests {
+		if fields := failedFields(Validate(&test.value)); !reflect.DeepEqual(fields, test.expected) {
+			t.Errorf("Test failed: Expected %v and received %v.", test.expected, fields)
+		}
+	}
+
+	if err := Validate(map[string]string{}); err != nil {
+		t.Errorf("Test failed: Expected a map to be valid and received %v.", err)
+	}
+}
+
+// TestValidatorRules checks that rules can be added to a validator without changing the default validator, and that
+// a tag naming an unknown rule is returned as an error.
+func TestValidatorRules(t *testing.T) {
+	var slug struct {
+		Slug string `json:"slug" validate:"lowercase"`
+	}
+	slug.Slug = "Hello"
+
+	validator := NewValidator()
+	validator.RegisterRule("lowercase", func(value reflect.Value, param string) error {
+		if value.String() != strings.ToLower(value.String()) {
+			return errors.New("must be lower case")
+		}
+		return nil
+	})
+	err := validator.Validate(slug)
+	if expected := "http_router: invalid fields: slug must be lower case"; err == nil || err.Error() != expected {
+		t.Errorf("Test failed: Expected %s and received %v.", expected, err)
+	}
+
+	err = Validate(slug)
+	if expected := "http_router: unknown validation rule lowercase for slug"; err == nil || err.Error() != expected {
+		t.Errorf("Test failed: Expected %s and received %v.", expected, err)
+	}
+	if ErrorStatus(err) != http.StatusInternalServerError {
+		t.Errorf("Test failed: Expected %d and received %d.", http.StatusInternalServerError, ErrorStatus(err))
+	}
+}
+
+// TestValidateBinding checks that typed handlers never see invalid input, which is answered with a 422 listing every
+// invalid field, and that ProblemJSON lists them as an extension member.
+func TestValidateBinding(t *testing.T) {
+	called := false
+	router := NewRouter()
+	router.AddErrorRoute(httpPost, "/signup", Typed(func(ctx context.Context, in signup) (signup, error) {
+		called = true
+		return in, nil
+	}))
+
+	request := httptest.NewRequest(httpPost, "http://localhost:8080/signup", strings.NewReader(`{"role":"owner"}`))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	expected := "422 http_router: invalid fields: name is required; role must be one of admin, member; " +
+		"home.city is required\n"
+	if called || recorder.Code != http.StatusUnprocessableEntity || recorder.Body.String() != expected {
+		t.Errorf("Test failed: Expected %s and received %d %s.", expected, recorder.Code, recorder.Body)
+	}
+
+	recorder = httptest.NewRecorder()
+	ProblemJSON(recorder, request, http.StatusUnprocessableEntity, Validate(&signup{Role: "admin", Name: "bob"}))
+	var problem Problem
+	json.Unmarshal(recorder.Body.Bytes(), &problem)
+	if len(problem.Errors) != 1 || problem.Errors[0] != (FieldError{"home.city", "required", "is required"}) {
+		t.Errorf("Test failed: Expected home.city to be listed and received %+v.", problem.Errors)
+	}
+}
+
+// TestValidateUnexportedEmbedded checks that the fields of a struct embedded under an unexported type, which binding
+// fills, are validated too, so typed handlers never see them invalid.
+func TestValidateUnexportedEmbedded(t *testing.T) {
+	type base struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type person struct {
+		base
+		Age int `json:"age"`
+	}
+	router := NewRouter()
+	router.AddErrorRoute(httpPost, "/people", Typed(func(ctx context.Context, in person) (person, error) {
+		return in, nil
+	}))
+
+	request := httptest.NewRequest(httpPost, "http://localhost:8080/people", strings.NewReader(`{"age":3}`))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if expected := "422 http_router: invalid fields: name is required\n"; recorder.Body.String() != expected {
+		t.Errorf("Test failed: Expected %s and received %d %s.", expected, recorder.Code, recorder.Body)
+	}
+
+	defer func() {
+		expected := "http_router: unknown validation rule requird for name"
+		if recovered := recover(); fmt.Sprint(recovered) != expected {
+			t.Errorf("Test failed: Expected %s and received %v.", expected, recovered)
+		}
+	}()
+	type badBase struct {
+		Name string `json:"name" validate:"requird"`
+	}
+	Typed(func(ctx context.Context, in struct{ badBase }) (string, error) { return "", nil })
+}
+
+// TestValidateTagMistakes checks that typed handlers whose input has mistakes in its validate tags, including in the
+// structs it holds, panic when they are created instead of on every request.
+func TestValidateTagMistakes(t *testing.T) {
+	type item struct {
+		Name string `json:"name" validate:"requried"`
+	}
+	tests := map[string]func(){
+		"http_router: unknown validation rule requried for items[].name": func() {
+			Typed(func(ctx context.Context, in struct {
+				Items []*item `json:"items"`
+			}) (string, error) {
+				return "", nil
+			})
+		},
+		`http_router: invalid validation rule max=ten for name: invalid bound "ten"`: func() {
+			Typed(func(ctx context.Context, in struct {
+				Name string `json:"name" validate:"max=ten"`
+			}) (string, error) {
+				return "", nil
+			})
+		},
+		"http_router: invalid validation rule min=1 for Admin: can't bound a field of type bool": func() {
+			Typed(func(ctx context.Context, in struct {
+				Admin *bool `validate:"min=1"`
+			}) (string, error) {
+				return "", nil
+			})
+		},
+		"http_router: invalid validation rule email for id: can't check a field of type int": func() {
+			Typed(func(ctx context.Context, in struct {
+				ID int `query:"id" validate:"email"`
+			}) (string, error) {
+				return "", nil
+			})
+		},
+	}
+	for expected, create := range tests {
+		func() {
+			defer func() {
+				if recovered := recover(); fmt.Sprint(recovered) != expected {
+					t.Errorf("Test failed: Expected %s and received %v.", expected, recovered)
+				}
+			}()
+			create()
+		}()
+	}
+
+	// well formed inputs, including recursive ones, are accepted
+	type tree struct {
+		Label    string `json:"label" validate:"required,max=8"`
+		Children []tree `json:"children" validate:"max=4"`
+	}
+	Typed(func(ctx context.Context, in tree) (tree, error) { return in, nil })
+	Typed(func(ctx context.Context, in signup) (signup, error) { return in, nil })
+}
@@ -0,0 +1,193 @@
+/*****************************************************************************
+ * walk.go
+ * Name: Nicholas Nguyen
+ * NetId: nn5029
+ *****************************************************************************/
+
+package http_router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// WalkFunc is called by Walk for every route, with the number of middlewares wrapping it, including the router's
+type WalkFunc func(method string, pattern string, name string, middlewares int) error
+
+// RouteInfo describes a route in the route table, along with the routes that take precedence over it for some of the
+// requests it matches
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Host        string   `json:"host,omitempty"`
+	Pattern     string   `json:"pattern"`
+	Name        string   `json:"name,omitempty"`
+	Matchers    []string `json:"matchers,omitempty"`
+	Middlewares int      `json:"middlewares"`
+	ShadowedBy  []string `json:"shadowed_by,omitempty"`
+}
+
+// Walk calls walkFn for every route, ordered by method and then by precedence, so that a route comes after the routes
+// taking precedence over it. Patterns are given with a leading '/', preceded by the host pattern of routes scoped to a
+// host, e.g. `api.example.com/users`. Routes without a host come first. Walk stops at the first error walkFn returns.
+func (router *HTTPRouter) Walk(walkFn WalkFunc) error {
+	table := router.loadTable()
+	return table.walk(func(route *RoutesField) error {
+		middlewares := len(table.middlewares) + len(route.Middlewares)
+		return walkFn(route.Method, route.displayPattern(), route.Name, middlewares)
+	})
+}
+
+// RouteTable returns every route as Walk orders them. A route is shadowed by an earlier route of the same method and
+// host when some request could match both, in which case the earlier route serves it if the request meets its
+// matchers.
+func (router *HTTPRouter) RouteTable() []RouteInfo {
+	var infos []RouteInfo
+	var methodRoutes [][]string
+	table := router.loadTable()
+	table.walk(func(route *RoutesField) error {
+		if len(infos) > 0 && (infos[len(infos)-1].Method != route.Method || infos[len(infos)-1].Host != route.Host) {
+			methodRoutes = nil
+		}
+
+		info := RouteInfo{
+			Method:      route.Method,
+			Host:        route.Host,
+			Pattern:     route.displayPattern(),
+			Name:        route.Name,
+			Middlewares: len(table.middlewares) + len(route.Middlewares),
+		}
+		for _, matcher := range route.Matchers {
+			info.Matchers = append(info.Matchers, matcher.Name)
+		}
+		segments := splitPattern(route.Pattern)
+		for _, earlier := range methodRoutes {
+			if overlaps(earlier, segments) {
+				info.ShadowedBy = append(info.ShadowedBy, route.Host+"/"+strings.Join(earlier, "/"))
+			}
+		}
+
+		methodRoutes = append(methodRoutes, segments)
+		infos = append(infos, info)
+		return nil
+	})
+	return infos
+}
+
+// DebugHandler returns a handler printing the route table, which can be mounted with AddRoute. It answers with JSON
+// if the request has a `format=json` query or accepts application/json, and with a plain text table otherwise.
+func (router *HTTPRouter) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		table := router.RouteTable()
+
+		if request.URL.Query().Get("format") == "json" ||
+			strings.Contains(request.Header.Get("Accept"), "application/json") {
+			response.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(response).Encode(table)
+			return
+		}
+
+		response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer := tabwriter.NewWriter(response, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "METHOD\tPATTERN\tNAME\tMATCHERS\tMIDDLEWARES\tSHADOWED BY")
+		for _, info := range table {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d\t%s\n", info.Method, info.Pattern, info.Name,
+				strings.Join(info.Matchers, ", "), info.Middlewares, strings.Join(info.ShadowedBy, ", "))
+		}
+		writer.Flush()
+	})
+}
+
+//----------------------------------------------------------------------------------------------------------------------
+
+// walk calls walkFn for every route, the routes without a host first and then the routes of each host pattern by
+// precedence, each ordered by method and then by the order lookup tries them in
+func (table *routeTable) walk(walkFn func(route *RoutesField) error) error {
+	if err := walkTrees(table.trees, walkFn); err != nil {
+		return err
+	}
+	for _, host := range table.hosts {
+		if err := walkTrees(host.trees, walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkTrees is a helper function for walk that walks the tries of trees ordered by method
+func walkTrees(trees map[string]*node, walkFn func(route *RoutesField) error) error {
+	methods := make([]string, 0, len(trees))
+	for method := range trees {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		if err := trees[method].walk(walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayPattern returns the pattern of route as Walk gives it, with a leading '/' preceded by its host pattern
+func (route *RoutesField) displayPattern() string {
+	return route.Host + "/" + route.Pattern
+}
+
+// walk calls walkFn for the route of every node under n, in the order lookup tries them in
+func (n *node) walk(walkFn func(route *RoutesField) error) error {
+	for _, route := range n.routes {
+		if err := walkFn(route); err != nil {
+			return err
+		}
+	}
+
+	static := map[string]*node{}
+	var names []string
+	n.static.each(func(name string, child *node) {
+		static[name] = child
+		names = append(names, name)
+	})
+	sort.Strings(names)
+
+	ordered := make([]*node, 0, len(names)+len(n.captures)+1)
+	for _, name := range names {
+		ordered = append(ordered, static[name])
+	}
+	ordered = append(ordered, n.captures...)
+	if n.catchAll != nil {
+		ordered = append(ordered, n.catchAll)
+	}
+
+	for _, child := range ordered {
+		if err := child.walk(walkFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether some request path could match both patterns, given as directories. Two captures are
+// assumed to overlap even if their constraints might never accept the same directory.
+func overlaps(first []string, second []string) bool {
+	for i := 0; i < len(first) && i < len(second); i++ {
+		// a catch-all matches the one or more directories the other pattern has left
+		if isCatchAll(first[i]) || isCatchAll(second[i]) {
+			return true
+		}
+		firstStatic, secondStatic := !isCapture(first[i]), !isCapture(second[i])
+		switch {
+		case firstStatic && secondStatic && first[i] != second[i]:
+			return false
+		case firstStatic && !secondStatic && !matchesConstraint(second[i], first[i]):
+			return false
+		case !firstStatic && secondStatic && !matchesConstraint(first[i], second[i]):
+			return false
+		}
+	}
+	return len(first) == len(second)
+}
@@ -0,0 +1,108 @@
+/******************************************************************************
+ *  walk_test.go
+ *  Usage:    `go test`  or  `go test -v`
+ *  Description:
+ *    Tests for walking the route table and the debug handler.
+ ******************************************************************************/
+
+package http_router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/******************************************************************************/
+/*                                 Helpers                                    */
+/******************************************************************************/
+
+// newWalkRouter creates a router with overlapping routes of two methods
+func newWalkRouter() *HTTPRouter {
+	router := NewRouter()
+	router.Use(tagMiddleware("a"))
+	router.AddRoute(httpGet, "/static/*file", echoParams, WithName("static"))
+	router.AddRoute(httpGet, "/users/:name", echoParams, WithMiddleware(tagMiddleware("b")))
+	router.AddRoute(httpGet, "/users/:id<int>", echoParams)
+	router.AddRoute(httpGet, "/users/me", echoParams)
+	router.AddRoute(httpGet, "/static/:dir/index.html", echoParams)
+	router.AddRoute(httpPost, "/users", echoParams)
+	return router
+}
+
+/******************************************************************************/
+/*                                  Tests                                     */
+/******************************************************************************/
+
+// TestWalkOrder checks that Walk visits routes by method and precedence, and stops at the first error.
+func TestWalkOrder(t *testing.T) {
+	router := newWalkRouter()
+
+	var walked []string
+	err := router.Walk(func(method string, pattern string, name string, middlewares int) error {
+		walked = append(walked, fmt.Sprintf("%s %s %s %d", method, pattern, name, middlewares))
+		return nil
+	})
+	expected := []string{
+		"GET /static/:dir/index.html  1",
+		"GET /static/*file static 1",
+		"GET /users/me  1",
+		"GET /users/:id<int>  1",
+		"GET /users/:name  2",
+		"POST /users  1",
+	}
+	if err != nil || !reflect.DeepEqual(walked, expected) {
+		t.Errorf("Test failed: Expected %q and received %q, %v.", expected, walked, err)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	err = router.Walk(func(method string, pattern string, name string, middlewares int) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Test failed: Expected Walk to stop at the first error, walked %d routes.", count)
+	}
+}
+
+// TestRouteTableShadowing checks that the route table lists the earlier routes overlapping each route.
+func TestRouteTableShadowing(t *testing.T) {
+	router := newWalkRouter()
+
+	request := httptest.NewRequest(httpGet, "http://localhost:8080/debug/routes?format=json", nil)
+	recorder := httptest.NewRecorder()
+	router.DebugHandler().ServeHTTP(recorder, request)
+
+	var table []RouteInfo
+	if err := json.NewDecoder(recorder.Result().Body).Decode(&table); err != nil {
+		t.Fatalf("Test failed: Could not decode the route table: %v", err)
+	}
+
+	shadowedBy := map[string][]string{}
+	for _, info := range table {
+		shadowedBy[info.Method+" "+info.Pattern] = info.ShadowedBy
+	}
+	expected := map[string][]string{
+		"GET /static/:dir/index.html": nil,
+		"GET /static/*file":           {"/static/:dir/index.html"},
+		"GET /users/me":               nil,
+		"GET /users/:id<int>":         nil,
+		"GET /users/:name":            {"/users/me", "/users/:id<int>"},
+		"POST /users":                 nil,
+	}
+	if !reflect.DeepEqual(shadowedBy, expected) {
+		t.Errorf("Test failed: Expected %v and received %v.", expected, shadowedBy)
+	}
+
+	request = httptest.NewRequest(httpGet, "http://localhost:8080/debug/routes", nil)
+	recorder = httptest.NewRecorder()
+	router.DebugHandler().ServeHTTP(recorder, request)
+	if body := responseBodyToString(recorder.Result()); !strings.Contains(body, "/users/me, /users/:id<int>") {
+		t.Errorf("Test failed: Expected the text table to list shadowing routes and received:\n%s", body)
+	}
+}